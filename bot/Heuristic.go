@@ -0,0 +1,292 @@
+package bot
+
+import (
+	"errors"
+
+	"github.com/nolwn/go-hearts/hearts"
+)
+
+const (
+	cardKingOfSpades hearts.Card = 50
+	cardAceOfSpades  hearts.Card = 51
+)
+
+var errNoLegalCards = errors.New("there are no cards that can be played")
+
+// Heuristic is a bot that plays by a handful of rules of thumb. It passes away its
+// dangerous cards, ducks tricks that have points in them, dumps the Queen of Spades as
+// soon as it can, and avoids leading high spades while the Queen is still out there.
+//
+// A Heuristic remembers what it has seen during a round, so each seat needs its own.
+type Heuristic struct {
+
+	// queenGone is set once the Queen of Spades has been seen in a trick this round.
+	queenGone bool
+
+	// round is the round that the bot's memory is about.
+	round int
+}
+
+// NewHeuristic creates a Heuristic bot.
+func NewHeuristic() *Heuristic {
+	return &Heuristic{}
+}
+
+// ChoosePass passes the three most dangerous cards in the hand: the Queen of Spades and
+// the spades above her first, then the highest hearts, then the highest of the rest.
+func (b *Heuristic) ChoosePass(per hearts.Perspective) ([]hearts.Card, error) {
+	hand, err := toCards(per.Hand)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(hand) < 3 {
+		return nil, errors.New("there aren't enough cards to pass")
+	}
+
+	// pick the three most dangerous cards, one at a time
+	passing := make([]hearts.Card, 0, 3)
+
+	for len(passing) < 3 {
+		best := -1
+
+		for i, c := range hand {
+			if best == -1 || danger(c) > danger(hand[best]) {
+				best = i
+			}
+		}
+
+		passing = append(passing, hand[best])
+		hand = append(hand[:best], hand[best+1:]...)
+	}
+
+	return passing, nil
+}
+
+// ChoosePlay picks a card from the legal cards in the Perspective.
+func (b *Heuristic) ChoosePlay(per hearts.Perspective) (hearts.Card, error) {
+	legal, err := toCards(per.Legal)
+
+	if err != nil {
+		return hearts.Nobody, err
+	}
+
+	trick, err := toCards(per.ThisTrick)
+
+	if err != nil {
+		return hearts.Nobody, err
+	}
+
+	if err := b.observe(per); err != nil {
+		return hearts.Nobody, err
+	}
+
+	if len(legal) == 0 {
+		return hearts.Nobody, errNoLegalCards
+	}
+
+	if len(trick) == 0 {
+		return b.lead(legal), nil
+	}
+
+	onSuit := inSuit(legal, per.Suit)
+
+	if len(onSuit) > 0 {
+		return b.follow(onSuit, trick, per.Suit), nil
+	}
+
+	return b.discard(legal), nil
+}
+
+// discard picks the card to slough when the bot can't follow suit: the Queen if it has
+// her, then the spades that could catch her, then its highest heart, then its highest
+// card.
+func (b *Heuristic) discard(legal []hearts.Card) hearts.Card {
+	if has(legal, hearts.CardJamoke) {
+		return hearts.CardJamoke
+	}
+
+	if !b.queenGone {
+		for _, c := range []hearts.Card{cardAceOfSpades, cardKingOfSpades} {
+			if has(legal, c) {
+				return c
+			}
+		}
+	}
+
+	if held := inSuit(legal, hearts.SuitHearts); len(held) > 0 {
+		return highest(held)
+	}
+
+	return highest(legal)
+}
+
+// follow picks a card of the led suit. The bot plays the highest card that still loses
+// the trick. If it can't lose, it gets rid of its highest card, keeping the Queen back if
+// it can. If it is last to play and the trick has no points, it wins with its highest
+// card since doing so costs nothing.
+func (b *Heuristic) follow(onSuit []hearts.Card, trick []hearts.Card, led string) hearts.Card {
+	winning := highest(inSuit(trick, led))
+	last := len(trick) == 3
+	safe := withoutCard(onSuit, hearts.CardJamoke)
+
+	if last && !hasPoints(trick) && len(safe) > 0 {
+		return highest(safe)
+	}
+
+	below := []hearts.Card{}
+
+	for _, c := range onSuit {
+		if c < winning {
+			below = append(below, c)
+		}
+	}
+
+	if len(below) > 0 {
+		return highest(below)
+	}
+
+	if len(safe) > 0 {
+		return highest(safe)
+	}
+
+	return highest(onSuit)
+}
+
+// lead picks the card to lead a trick with. The bot leads its lowest card, but avoids
+// hearts, the Queen, and, while the Queen is still out, the spades that could catch her.
+func (b *Heuristic) lead(legal []hearts.Card) hearts.Card {
+	candidates := []hearts.Card{}
+
+	for _, c := range legal {
+		risky := c.Suit() == hearts.SuitHearts || c == hearts.CardJamoke ||
+			(!b.queenGone && (c == cardKingOfSpades || c == cardAceOfSpades))
+
+		if !risky {
+			candidates = append(candidates, c)
+		}
+	}
+
+	if len(candidates) == 0 {
+		candidates = withoutCard(legal, hearts.CardJamoke)
+	}
+
+	if len(candidates) == 0 {
+		return hearts.CardJamoke
+	}
+
+	return lowest(candidates)
+}
+
+// observe updates what the bot remembers from the cards it can see.
+func (b *Heuristic) observe(per hearts.Perspective) error {
+	if per.Round != b.round {
+		b.round = per.Round
+		b.queenGone = false
+	}
+
+	for _, cards := range [][]hearts.JSONCard{per.LastTrick, per.ThisTrick} {
+		seen, err := toCards(cards)
+
+		if err != nil {
+			return err
+		}
+
+		if has(seen, hearts.CardJamoke) {
+			b.queenGone = true
+		}
+	}
+
+	return nil
+}
+
+// danger scores how much trouble a card is likely to cause its holder. The higher the
+// score, the sooner the card should be passed.
+func danger(c hearts.Card) int {
+	switch {
+	case c == hearts.CardJamoke:
+		return 100
+	case c == cardAceOfSpades || c == cardKingOfSpades:
+		return 80 + rank(c)
+	case c.Suit() == hearts.SuitHearts:
+		return 40 + rank(c)
+	case c.Suit() == hearts.SuitSpades: // low spades protect against the Queen
+		return rank(c) - 13
+	default:
+		return rank(c)
+	}
+}
+
+func has(cards []hearts.Card, card hearts.Card) bool {
+	for _, c := range cards {
+		if c == card {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasPoints(cards []hearts.Card) bool {
+	for _, c := range cards {
+		if c.Suit() == hearts.SuitHearts || c == hearts.CardJamoke {
+			return true
+		}
+	}
+
+	return false
+}
+
+func highest(cards []hearts.Card) hearts.Card {
+	best := cards[0]
+
+	for _, c := range cards {
+		if rank(c) > rank(best) {
+			best = c
+		}
+	}
+
+	return best
+}
+
+func inSuit(cards []hearts.Card, suit string) []hearts.Card {
+	matching := []hearts.Card{}
+
+	for _, c := range cards {
+		if c.Suit() == suit {
+			matching = append(matching, c)
+		}
+	}
+
+	return matching
+}
+
+func lowest(cards []hearts.Card) hearts.Card {
+	best := cards[0]
+
+	for _, c := range cards {
+		if rank(c) < rank(best) {
+			best = c
+		}
+	}
+
+	return best
+}
+
+// rank returns the rank of a card within its suit, from 0 for a Two to 12 for an Ace.
+func rank(c hearts.Card) int {
+	return int(c) % 13
+}
+
+func withoutCard(cards []hearts.Card, card hearts.Card) []hearts.Card {
+	without := []hearts.Card{}
+
+	for _, c := range cards {
+		if c != card {
+			without = append(without, c)
+		}
+	}
+
+	return without
+}
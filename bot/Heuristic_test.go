@@ -0,0 +1,139 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/nolwn/go-hearts/hearts"
+)
+
+// As a helpful reminder:
+// Diamonds 0–12
+// Clubs 	13–25
+// Hearts 	26–38
+// Spades 	39–51
+
+func TestHeuristicPass(t *testing.T) {
+	b := NewHeuristic()
+	per := hearts.Perspective{
+		Hand:  jsonCards(0, 12, 13, 25, 26, 38, 39, 40, 49, 51),
+		Phase: "pass",
+		Round: 1,
+	}
+
+	passing, err := b.ChoosePass(per)
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	// the Queen, the Ace of Spades and the Ace of Hearts
+	checkCards(t, passing, hearts.CardJamoke, 51, 38)
+}
+
+func TestHeuristicDucksPoints(t *testing.T) {
+	b := NewHeuristic()
+
+	// a heart has been dropped onto a Ten of Diamonds
+	per := playPerspective(1, []hearts.Card{8, 30}, hearts.SuitDiamonds, 2, 7, 10)
+
+	checkPlay(t, b, per, 7) // the Nine ducks under the Ten
+}
+
+func TestHeuristicWinsCleanTrick(t *testing.T) {
+	b := NewHeuristic()
+
+	// last to play, with no points in the trick
+	per := playPerspective(1, []hearts.Card{8, 3, 5}, hearts.SuitDiamonds, 2, 7, 10)
+
+	checkPlay(t, b, per, 10)
+}
+
+func TestHeuristicDumpsQueen(t *testing.T) {
+	b := NewHeuristic()
+
+	// void in diamonds
+	per := playPerspective(1, []hearts.Card{8}, hearts.SuitDiamonds, 14, 30, hearts.CardJamoke)
+
+	checkPlay(t, b, per, hearts.CardJamoke)
+
+	// someone else has led the King of Spades
+	per = playPerspective(1, []hearts.Card{50}, hearts.SuitSpades, 41, hearts.CardJamoke)
+
+	checkPlay(t, b, per, hearts.CardJamoke)
+}
+
+func TestHeuristicLeads(t *testing.T) {
+	b := NewHeuristic()
+
+	// with the Queen still out, the bot leads the Ace of Diamonds over the King of Spades
+	per := playPerspective(1, nil, "", 12, 50)
+
+	checkPlay(t, b, per, 12)
+
+	// once the Queen has been seen, the King of Spades is the lower card
+	per.LastTrick = jsonCards(39, 40, hearts.CardJamoke, 42)
+
+	checkPlay(t, b, per, 50)
+
+	// in a new round, the Queen is out again
+	per.LastTrick = nil
+	per.Round = 2
+
+	checkPlay(t, b, per, 12)
+
+	// hearts are never led if there's something else
+	per = playPerspective(1, nil, "", 26, 30, 48)
+
+	checkPlay(t, b, per, 48)
+}
+
+func checkCards(t *testing.T, cards []hearts.Card, expected ...hearts.Card) {
+	t.Helper()
+
+	if len(cards) != len(expected) {
+		t.Fatalf("expected %v but received %v", expected, cards)
+	}
+
+	for i := range cards {
+		if cards[i] != expected[i] {
+			t.Errorf("expected %v but received %v", expected, cards)
+			return
+		}
+	}
+}
+
+func checkPlay(t *testing.T, b Player, per hearts.Perspective, expected hearts.Card) {
+	t.Helper()
+	card, err := b.ChoosePlay(per)
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	if card != expected {
+		t.Errorf("expected the %s but received the %s", expected, card)
+	}
+}
+
+func jsonCards(cards ...hearts.Card) []hearts.JSONCard {
+	converted := make([]hearts.JSONCard, 0, len(cards))
+
+	for _, c := range cards {
+		converted = append(converted, hearts.JSONCard{Suit: c.Suit(), Value: c.Value()})
+	}
+
+	return converted
+}
+
+// playPerspective returns a Perspective in the play phase in which every card in the
+// hand can be played.
+func playPerspective(round int, trick []hearts.Card, suit string, hand ...hearts.Card) hearts.Perspective {
+	return hearts.Perspective{
+		Hand:      jsonCards(hand...),
+		Legal:     jsonCards(hand...),
+		Phase:     "play",
+		Round:     round,
+		Suit:      suit,
+		ThisTrick: jsonCards(trick...),
+	}
+}
@@ -0,0 +1,620 @@
+package bot
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/nolwn/go-hearts/hearts"
+)
+
+const (
+	defaultDeals       = 20
+	defaultExploration = 0.7
+	defaultIterations  = 1000
+
+	// dealAttempts is how many times a deal is retried before the bot gives up on placing
+	// cards around the voids it knows about.
+	dealAttempts = 50
+)
+
+// MonteCarloOptions tune how much thinking a MonteCarlo bot does for each play.
+type MonteCarloOptions struct {
+
+	// Deals is how many deals of the cards the bot hasn't seen it searches over for a
+	// single play. Each deal is made once, and every search plays out a copy of one of
+	// them. It defaults to 20.
+	Deals int
+
+	// Iterations is the most searches the bot will run for a single play. If both
+	// Iterations and Time are 0, the bot runs 1000 searches.
+	Iterations int
+
+	// Time is the most time the bot will spend on a single play. If it is 0, only
+	// Iterations limits the bot.
+	Time time.Duration
+
+	// Exploration is how much the search favours trying plays it knows little about over
+	// plays that have done well so far. It defaults to 0.7.
+	Exploration float64
+
+	// Seed seeds the bot's random numbers, so that its play can be reproduced. If it is 0,
+	// the bot is seeded from the clock.
+	Seed int64
+}
+
+// MonteCarlo is a bot that searches for its play. It can't see the other hands, so it
+// deals the cards it hasn't seen to the other seats in a few different ways that fit
+// everything it knows: the cards it passed, and the suits each seat has shown it is out
+// of. Each search clones one of those games and plays the rest of the round out with the
+// real rules, using information-set Monte Carlo tree search to decide which plays are
+// worth looking into. The play that was tried the most across all of the deals is the one
+// it makes.
+//
+// A MonteCarlo bot passes the way the Heuristic bot does.
+//
+// A MonteCarlo remembers what it has seen during a round, so each seat needs its own.
+type MonteCarlo struct {
+	deals       int
+	exploration float64
+	iterations  int
+	time        time.Duration
+
+	passer *Heuristic
+	rng    *rand.Rand
+
+	// leader is the seat that led trick number trick.
+	leader int
+
+	// passed are the cards the bot passed this round, and passedTo is who they went to.
+	passed   []hearts.Card
+	passedTo int
+
+	// played is every card the bot has seen played this round.
+	played [52]bool
+
+	// recorded is the number of the last trick the bot has taken note of.
+	recorded int
+
+	// round is the round that the bot's memory is about.
+	round int
+
+	// trick is the number of the trick the bot last played in.
+	trick int
+
+	// voids are the suits, by suit index, that each seat has shown it is out of.
+	voids [4][4]bool
+}
+
+// NewMonteCarlo creates a MonteCarlo bot. Only the first MonteCarloOptions is used.
+func NewMonteCarlo(options ...MonteCarloOptions) *MonteCarlo {
+	var opts MonteCarloOptions
+
+	if len(options) > 0 {
+		opts = options[0]
+	}
+
+	if opts.Iterations == 0 && opts.Time == 0 {
+		opts.Iterations = defaultIterations
+	}
+
+	if opts.Deals <= 0 {
+		opts.Deals = defaultDeals
+	}
+
+	if opts.Exploration == 0 {
+		opts.Exploration = defaultExploration
+	}
+
+	if opts.Seed == 0 {
+		opts.Seed = time.Now().UnixNano()
+	}
+
+	return &MonteCarlo{
+		deals:       opts.Deals,
+		exploration: opts.Exploration,
+		iterations:  opts.Iterations,
+		time:        opts.Time,
+		passer:      NewHeuristic(),
+		rng:         rand.New(rand.NewSource(opts.Seed)),
+		leader:      hearts.Nobody,
+		passedTo:    hearts.Nobody,
+	}
+}
+
+// ChoosePass passes the same cards the Heuristic bot would, and remembers where they went.
+func (b *MonteCarlo) ChoosePass(per hearts.Perspective) ([]hearts.Card, error) {
+	passing, err := b.passer.ChoosePass(per)
+
+	if err != nil {
+		return nil, err
+	}
+
+	b.forget(per.Round)
+	b.passed = passing
+	b.passedTo = hearts.PassTarget(per.Round, per.Seat-1)
+
+	return passing, nil
+}
+
+// ChoosePlay searches for the best of the legal cards in the Perspective.
+func (b *MonteCarlo) ChoosePlay(per hearts.Perspective) (hearts.Card, error) {
+	legal, err := toCards(per.Legal)
+
+	if err != nil {
+		return hearts.Nobody, err
+	}
+
+	if err := b.observe(per); err != nil {
+		return hearts.Nobody, err
+	}
+
+	if len(legal) == 0 {
+		return hearts.Nobody, errNoLegalCards
+	}
+
+	if len(legal) == 1 {
+		return legal[0], nil
+	}
+
+	return b.search(per)
+}
+
+// node is a play in the search tree. The children of a node are the plays that have been
+// tried after it in any of the deals.
+type node struct {
+	card     hearts.Card
+	children []*node
+	parent   *node
+
+	// seat is the seat that made the play, or Nobody for the root.
+	seat int
+
+	// avail is the number of times the play could have been chosen, visits is the number
+	// of times it was, and reward is the total reward the seat received for it.
+	avail  int
+	reward float64
+	visits int
+}
+
+// search runs the tree search until the bot's budget runs out, and returns the root play
+// that was visited the most. It always finishes at least one iteration, so there is a play
+// to return however small the time budget is.
+func (b *MonteCarlo) search(per hearts.Perspective) (hearts.Card, error) {
+	root := &node{seat: hearts.Nobody}
+	start := time.Now()
+	deals := b.deals
+
+	if b.iterations > 0 && b.iterations < deals {
+		deals = b.iterations
+	}
+
+	games := make([]hearts.Hearts, 0, deals)
+
+	for len(games) < deals {
+		pos, err := b.determinize(per)
+
+		if err != nil {
+			return hearts.Nobody, err
+		}
+
+		game, err := hearts.FromPosition(pos)
+
+		if err != nil {
+			return hearts.Nobody, err
+		}
+
+		games = append(games, game)
+	}
+
+	for i := 0; b.iterations == 0 || i < b.iterations; i++ {
+		if i > 0 && b.time > 0 && time.Since(start) >= b.time {
+			break
+		}
+
+		game := games[i%len(games)].Clone()
+
+		if err := b.iterate(root, &game); err != nil {
+			return hearts.Nobody, err
+		}
+	}
+
+	var best *node
+
+	for _, child := range root.children {
+		if best == nil || child.visits > best.visits ||
+			(child.visits == best.visits && child.reward > best.reward) {
+			best = child
+		}
+	}
+
+	if best == nil {
+		return hearts.Nobody, errors.New("there wasn't time to search for a play")
+	}
+
+	return best.card, nil
+}
+
+// iterate runs one search through the tree: it follows the most promising plays that are
+// legal in this deal, adds the first play it hasn't tried yet, plays randomly to the end
+// of the round and then credits every play it made with how the round turned out.
+func (b *MonteCarlo) iterate(root *node, game *hearts.Hearts) error {
+	round := game.Round()
+	before := game.Score()
+	n := root
+
+	for !roundOver(game, round) {
+		seat := game.PlayersTurn()[0]
+		legal := game.LegalMoves(seat)
+		untried := []hearts.Card{}
+
+		for _, c := range legal {
+			if n.child(c) == nil {
+				untried = append(untried, c)
+			}
+		}
+
+		for _, child := range n.children {
+			if has(legal, child.card) {
+				child.avail++
+			}
+		}
+
+		if len(untried) > 0 {
+			child := &node{
+				card:   untried[b.rng.Intn(len(untried))],
+				parent: n,
+				seat:   seat,
+				avail:  1,
+			}
+
+			n.children = append(n.children, child)
+			n = child
+
+			if err := game.Apply(hearts.PlayMove{Seat: seat, Card: child.card}); err != nil {
+				return err
+			}
+
+			break
+		}
+
+		n = b.selectChild(n, legal)
+
+		if err := game.Apply(hearts.PlayMove{Seat: seat, Card: n.card}); err != nil {
+			return err
+		}
+	}
+
+	// play the rest of the round out at random
+	for !roundOver(game, round) {
+		seat := game.PlayersTurn()[0]
+		legal := game.LegalMoves(seat)
+
+		if err := game.Apply(hearts.PlayMove{Seat: seat, Card: legal[b.rng.Intn(len(legal))]}); err != nil {
+			return err
+		}
+	}
+
+	rewards := roundRewards(before, game.Score())
+
+	for ; n != nil; n = n.parent {
+		n.visits++
+
+		if n.seat != hearts.Nobody {
+			n.reward += rewards[n.seat]
+		}
+	}
+
+	return nil
+}
+
+// selectChild picks the child, out of the ones that are legal in this deal, with the
+// highest upper confidence bound.
+func (b *MonteCarlo) selectChild(n *node, legal []hearts.Card) *node {
+	var best *node
+	bestScore := math.Inf(-1)
+
+	for _, child := range n.children {
+		if !has(legal, child.card) {
+			continue
+		}
+
+		visits := float64(child.visits)
+		score := child.reward/visits + b.exploration*math.Sqrt(math.Log(float64(child.avail))/visits)
+
+		if score > bestScore {
+			best = child
+			bestScore = score
+		}
+	}
+
+	return best
+}
+
+func (n *node) child(card hearts.Card) *node {
+	for _, child := range n.children {
+		if child.card == card {
+			return child
+		}
+	}
+
+	return nil
+}
+
+// determinize deals the cards the bot hasn't seen to the other seats. Cards the bot passed
+// go back to the seat it passed them to, and no seat is given a suit it has shown it is
+// out of. If there's no way to honour the voids, they are ignored.
+func (b *MonteCarlo) determinize(per hearts.Perspective) (hearts.Position, error) {
+	seat := per.Seat - 1
+	pos := hearts.Position{
+		Broken:       per.Broken,
+		PointsToLose: per.PointsToLose,
+		Round:        per.Round,
+		Rules:        per.Rules,
+		TrickNumber:  per.Trick,
+	}
+
+	hand, err := toCards(per.Hand)
+
+	if err != nil {
+		return pos, err
+	}
+
+	trick, err := toCards(per.ThisTrick)
+
+	if err != nil {
+		return pos, err
+	}
+
+	pos.Trick = trick
+	copy(pos.Points[:], per.RoundPoints)
+	pos.Leader = b.leader
+	pos.Hands[seat] = hand
+
+	// seats that have played into this trick hold one card fewer than the bot
+	need := [4]int{}
+	p := b.leader
+
+	for s := range need {
+		if s != seat {
+			need[s] = len(hand)
+		}
+	}
+
+	for range trick {
+		need[p]--
+		p = nextSeat(p)
+	}
+
+	// everything the bot can't account for is in someone else's hand
+	unseen := []hearts.Card{}
+	known := b.played
+
+	for _, c := range append(hand, trick...) {
+		known[c] = true
+	}
+
+	for _, c := range b.passed {
+		if !known[c] && need[b.passedTo] > 0 {
+			pos.Hands[b.passedTo] = append(pos.Hands[b.passedTo], c)
+			need[b.passedTo]--
+			known[c] = true
+		}
+	}
+
+	for c := range known {
+		if !known[c] {
+			unseen = append(unseen, hearts.Card(c))
+		}
+	}
+
+	total := 0
+
+	for _, n := range need {
+		total += n
+	}
+
+	if total > len(unseen) {
+		return pos, errors.New("there aren't enough unseen cards to go around")
+	}
+
+	for attempt := 0; attempt <= dealAttempts; attempt++ {
+		dealt, ok := b.deal(unseen, need, attempt < dealAttempts)
+
+		if ok {
+			for s := range dealt {
+				pos.Hands[s] = append(pos.Hands[s], dealt[s]...)
+			}
+
+			break
+		}
+	}
+
+	return pos, nil
+}
+
+// deal hands out the unseen cards at random until every seat has the number of cards it
+// needs. Any cards left over were played in tricks the bot didn't see. If respectVoids is
+// set, a seat is never given a suit it is out of, and false is returned if that leaves a
+// card with nowhere to go.
+func (b *MonteCarlo) deal(unseen []hearts.Card, need [4]int, respectVoids bool) ([4][]hearts.Card, bool) {
+	dealt := [4][]hearts.Card{}
+	deck := make([]hearts.Card, len(unseen))
+	copy(deck, unseen)
+	b.rng.Shuffle(len(deck), func(i, j int) { deck[i], deck[j] = deck[j], deck[i] })
+
+	remaining := 0
+
+	for _, n := range need {
+		remaining += n
+	}
+
+	spare := len(deck) - remaining
+
+	for _, c := range deck {
+		if remaining == 0 {
+			break
+		}
+
+		// pick a seat with room for the card, weighted by how much room it has
+		room := 0
+
+		for s, n := range need {
+			if !respectVoids || !b.voids[s][suitIndex(c)] {
+				room += n
+			}
+		}
+
+		if room == 0 {
+			if spare > 0 {
+				spare-- // the card may have been played in a trick the bot didn't see
+				continue
+			}
+
+			return dealt, false
+		}
+
+		pick := b.rng.Intn(room)
+
+		for s, n := range need {
+			if respectVoids && b.voids[s][suitIndex(c)] {
+				continue
+			}
+
+			if pick < n {
+				dealt[s] = append(dealt[s], c)
+				need[s]--
+				remaining--
+				break
+			}
+
+			pick -= n
+		}
+	}
+
+	return dealt, remaining == 0
+}
+
+// forget clears the bot's memory at the start of a new round.
+func (b *MonteCarlo) forget(round int) {
+	b.leader = hearts.Nobody
+	b.passed = nil
+	b.passedTo = hearts.Nobody
+	b.played = [52]bool{}
+	b.recorded = 0
+	b.round = round
+	b.trick = 0
+	b.voids = [4][4]bool{}
+}
+
+// observe updates what the bot remembers from the cards it can see. The last trick is
+// shown in seat order, so the bot relies on remembering who led it to work out who
+// didn't follow suit.
+func (b *MonteCarlo) observe(per hearts.Perspective) error {
+	seat := per.Seat - 1
+
+	if per.Round != b.round {
+		b.forget(per.Round)
+	}
+
+	last, err := toCards(per.LastTrick)
+
+	if err != nil {
+		return err
+	}
+
+	trick, err := toCards(per.ThisTrick)
+
+	if err != nil {
+		return err
+	}
+
+	if len(last) == 4 && per.Took > 0 && b.recorded < per.Trick-1 {
+		inOrder := []hearts.Card{}
+
+		if b.trick == per.Trick-1 && b.leader != hearts.Nobody {
+			for i, p := 0, b.leader; i < 4; i, p = i+1, nextSeat(p) {
+				inOrder = append(inOrder, last[p])
+			}
+		}
+
+		b.see(inOrder, b.leader)
+
+		for _, c := range last {
+			b.played[c] = true
+		}
+
+		b.recorded = per.Trick - 1
+	}
+
+	// the Two of Clubs always starts the round
+	if per.Trick > 1 {
+		b.played[hearts.CardTwoOfClubs] = true
+	}
+
+	b.trick = per.Trick
+	b.leader = seat
+
+	if len(trick) > 0 {
+		b.leader = per.Leader - 1
+	}
+
+	b.see(trick, b.leader)
+
+	for _, c := range trick {
+		b.played[c] = true
+	}
+
+	return nil
+}
+
+// see notes the voids shown by a trick played in order, starting with the leader.
+func (b *MonteCarlo) see(trick []hearts.Card, leader int) {
+	if len(trick) == 0 {
+		return
+	}
+
+	led := suitIndex(trick[0])
+
+	for i, p := 0, leader; i < len(trick); i, p = i+1, nextSeat(p) {
+		if suitIndex(trick[i]) != led {
+			b.voids[p][led] = true
+		}
+	}
+}
+
+// nextSeat returns the seat that plays after the given one.
+func nextSeat(seat int) int {
+	return (seat + 3) % 4
+}
+
+// roundOver returns true once the round the search started in has been scored.
+func roundOver(game *hearts.Hearts, round int) bool {
+	return game.Finished() || game.Round() != round
+}
+
+// roundRewards scores how well each seat did in a round, between 0 and 1. A seat's reward
+// depends on the points it lost compared to the average lost by the other seats.
+func roundRewards(before map[int]int, after map[int]int) [4]float64 {
+	lost := [4]float64{}
+	total := 0.0
+
+	for s := range lost {
+		lost[s] = float64(before[s] - after[s])
+		total += lost[s]
+	}
+
+	rewards := [4]float64{}
+
+	for s := range rewards {
+		others := (total - lost[s]) / 3
+		rewards[s] = math.Max(0, math.Min(1, 0.5+(others-lost[s])/(2*hearts.MoonPoints)))
+	}
+
+	return rewards
+}
+
+func suitIndex(c hearts.Card) int {
+	return int(c) / 13
+}
@@ -0,0 +1,167 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nolwn/go-hearts/hearts"
+)
+
+func TestMonteCarloDumpsQueen(t *testing.T) {
+	b := NewMonteCarlo(MonteCarloOptions{Iterations: 300, Seed: 1})
+
+	// void in diamonds, with player 2 having led
+	per := searchPerspective(1, 11, 2, []hearts.Card{8}, 14, 30, hearts.CardJamoke)
+
+	checkPlay(t, b, per, hearts.CardJamoke)
+}
+
+func TestMonteCarloDucksQueen(t *testing.T) {
+	b := NewMonteCarlo(MonteCarloOptions{Iterations: 300, Seed: 1})
+
+	// the Queen has been played under a Nine of Spades, and the Ace would take her
+	per := searchPerspective(1, 11, 3, []hearts.Card{46, hearts.CardJamoke}, 0, 40, 51)
+	per.Legal = jsonCards(40, 51)
+
+	checkPlay(t, b, per, 40)
+}
+
+func TestMonteCarloBudget(t *testing.T) {
+	b := NewMonteCarlo(MonteCarloOptions{Time: 20 * time.Millisecond, Seed: 1})
+	per := searchPerspective(1, 1, 1, nil, 0, 1, 2, 13, 14, 15, 26, 27, 28, 39, 40, 41, 42)
+	per.Legal = jsonCards(13)
+
+	start := time.Now()
+
+	// with only one legal card, there's nothing to think about
+	checkPlay(t, b, per, 13)
+
+	if elapsed := time.Since(start); elapsed >= 20*time.Millisecond {
+		t.Errorf("expected the bot to play its only card right away, but it took %s", elapsed)
+	}
+
+	per = searchPerspective(1, 11, 2, []hearts.Card{8}, 14, 30, hearts.CardJamoke)
+	start = time.Now()
+
+	if _, err := b.ChoosePlay(per); err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the bot to stop searching after 20ms, but it took %s", elapsed)
+	}
+}
+
+func TestMonteCarloTinyBudget(t *testing.T) {
+	b := NewMonteCarlo(MonteCarloOptions{Time: time.Microsecond, Seed: 1})
+	per := searchPerspective(1, 11, 2, []hearts.Card{8}, 14, 30, hearts.CardJamoke)
+
+	// the budget runs out before the deals are even built, but the bot still plays
+	card, err := b.ChoosePlay(per)
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	if !has([]hearts.Card{14, 30, hearts.CardJamoke}, card) {
+		t.Errorf("expected one of the bot's cards, but it played the %s", card)
+	}
+}
+
+func TestMonteCarloDeals(t *testing.T) {
+	b := NewMonteCarlo(MonteCarloOptions{Seed: 1})
+
+	passed, err := b.ChoosePass(hearts.Perspective{
+		Hand:   jsonCards(13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25),
+		Phase:  "pass",
+		PassTo: "left",
+		Round:  1,
+		Seat:   1,
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	// player 3 led a diamond, and player 2 showed they have none
+	per := searchPerspective(1, 2, 3, []hearts.Card{0, 26}, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 30, 31)
+	per.Broken = true
+	per.LastTrick = jsonCards(39, 40, 41, 42)
+	per.Took = 3
+	per.PointsToLose = []int{40, 60, 70, 80}
+
+	if err := b.observe(per); err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	for i := 0; i < 200; i++ {
+		pos, err := b.determinize(per)
+
+		if err != nil {
+			t.Fatalf("expected no error but received: %s", err)
+		}
+
+		game, err := hearts.FromPosition(pos)
+
+		if err != nil {
+			t.Fatalf("expected a deal that could be played, but received: %s", err)
+		}
+
+		// rollouts are scored from where the game stands, so moon shots are valued right
+		if game.Score()[hearts.PlayerOne] != 40 {
+			t.Fatalf("expected player 1 to start 40 points from losing, not %d", game.Score()[hearts.PlayerOne])
+		}
+
+		for _, c := range pos.Hands[hearts.PlayerTwo] {
+			if c.Suit() == hearts.SuitDiamonds {
+				t.Fatalf("expected player 2 to have no diamonds, but they were dealt the %s", c)
+			}
+		}
+
+		for _, c := range passed {
+			if !has(pos.Hands[hearts.PlayerFour], c) {
+				t.Fatalf("expected player 4 to hold the passed %s, but they hold %v", c, pos.Hands[hearts.PlayerFour])
+			}
+		}
+	}
+}
+
+func TestMonteCarloFullGame(t *testing.T) {
+	h := hearts.New(hearts.Options{Seed: 5})
+	h.Setup()
+
+	bots := map[int]Player{
+		hearts.PlayerOne:   NewMonteCarlo(MonteCarloOptions{Iterations: 30, Seed: 1}),
+		hearts.PlayerTwo:   NewHeuristic(),
+		hearts.PlayerThree: NewHeuristic(),
+		hearts.PlayerFour:  NewHeuristic(),
+	}
+
+	if err := Run(&h, bots); err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	if !h.Finished() {
+		t.Error("expected the bots to play the game to the end")
+	}
+}
+
+// searchPerspective returns a Perspective for the given seat id in the play phase, in the
+// middle of a trick led by the leader id. Every card in the hand can be played.
+func searchPerspective(seat int, trick int, leader int, played []hearts.Card, hand ...hearts.Card) hearts.Perspective {
+	suit := ""
+
+	if len(played) > 0 {
+		suit = played[0].Suit()
+	} else {
+		leader = seat
+	}
+
+	per := playPerspective(1, played, suit, hand...)
+	per.Leader = leader
+	per.Rules = hearts.DefaultRules()
+	per.Seat = seat
+	per.Trick = trick
+
+	return per
+}
@@ -0,0 +1,143 @@
+// Package bot plays Hearts for seats that don't have a person in them. Bots only ever see
+// what (*hearts.Hearts).From shows the seat they are sitting in, so they play by the same
+// information a person would have.
+package bot
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/nolwn/go-hearts/hearts"
+)
+
+// Player decides what a bot does on its turn.
+type Player interface {
+
+	// ChoosePass returns the three cards to pass, given what the bot's seat can see.
+	ChoosePass(per hearts.Perspective) ([]hearts.Card, error)
+
+	// ChoosePlay returns the card to play into the trick, given what the bot's seat can
+	// see.
+	ChoosePlay(per hearts.Perspective) (hearts.Card, error)
+}
+
+// The kinds of bot in this package, by the names they are stored under.
+const (
+	KindHeuristic  = "heuristic"
+	KindMonteCarlo = "montecarlo"
+)
+
+// Kind returns the name of the kind of bot the player is, or an empty string if it isn't
+// one of the bots in this package.
+func Kind(player Player) string {
+	switch player.(type) {
+	case *Heuristic:
+		return KindHeuristic
+	case *MonteCarlo:
+		return KindMonteCarlo
+	default:
+		return ""
+	}
+}
+
+// New creates a bot of the named kind, with its default options. It is used to bring
+// back a bot that was stored by name, so the new bot has none of the old one's memory of
+// the round.
+func New(kind string) (Player, error) {
+	switch kind {
+	case KindHeuristic:
+		return NewHeuristic(), nil
+	case KindMonteCarlo:
+		return NewMonteCarlo(), nil
+	default:
+		return nil, fmt.Errorf("unknown kind of bot %q", kind)
+	}
+}
+
+// Act has the player sitting in the seat take its turn, if it is that seat's turn. It
+// returns true if a move was made.
+func Act(game *hearts.Hearts, seat int, player Player) (bool, error) {
+	if game.Finished() || !isTurn(game, seat) {
+		return false, nil
+	}
+
+	b, err := game.From(seat)
+
+	if err != nil {
+		return false, err
+	}
+
+	var per hearts.Perspective
+
+	if err := json.Unmarshal(b, &per); err != nil {
+		return false, err
+	}
+
+	if game.Phase() == hearts.PhasePass {
+		cards, err := player.ChoosePass(per)
+
+		if err != nil {
+			return false, err
+		}
+
+		return true, game.Apply(hearts.PassMove{Seat: seat, Cards: cards})
+	}
+
+	card, err := player.ChoosePlay(per)
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, game.Apply(hearts.PlayMove{Seat: seat, Card: card})
+}
+
+// Run has the bots take turns for as long as it is one of their turns. Seats are keyed
+// by player index. Run stops once it is the turn of a seat without a bot, or once the
+// game is finished.
+func Run(game *hearts.Hearts, bots map[int]Player) error {
+	for {
+		acted := false
+
+		for seat, player := range bots {
+			ok, err := Act(game, seat, player)
+
+			if err != nil {
+				return fmt.Errorf("bot in seat %d: %w", seat, err)
+			}
+
+			acted = acted || ok
+		}
+
+		if !acted {
+			return nil
+		}
+	}
+}
+
+// toCards turns the cards in a Perspective back into Cards.
+func toCards(cards []hearts.JSONCard) ([]hearts.Card, error) {
+	converted := make([]hearts.Card, 0, len(cards))
+
+	for _, c := range cards {
+		card, err := hearts.NewCard(c.Value, c.Suit)
+
+		if err != nil {
+			return nil, err
+		}
+
+		converted = append(converted, card)
+	}
+
+	return converted, nil
+}
+
+func isTurn(game *hearts.Hearts, seat int) bool {
+	for _, p := range game.PlayersTurn() {
+		if p == seat {
+			return true
+		}
+	}
+
+	return false
+}
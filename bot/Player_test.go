@@ -0,0 +1,92 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/nolwn/go-hearts/hearts"
+)
+
+func TestRun(t *testing.T) {
+	h := hearts.New(hearts.Options{Seed: 3})
+	h.Setup()
+
+	bots := map[int]Player{
+		hearts.PlayerTwo:   NewHeuristic(),
+		hearts.PlayerThree: NewHeuristic(),
+		hearts.PlayerFour:  NewHeuristic(),
+	}
+
+	if err := Run(&h, bots); err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	// every bot has passed, so only player one is left
+	checkTurn(t, &h, hearts.PlayerOne)
+
+	hand := h.Players[hearts.PlayerOne].Hand
+
+	if err := h.Play(hearts.PlayerOne, hand[0], hand[1], hand[2]); err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	if err := Run(&h, bots); err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	// the bots play until it's player one's turn
+	checkTurn(t, &h, hearts.PlayerOne)
+
+	if ok, err := Act(&h, hearts.PlayerTwo, bots[hearts.PlayerTwo]); ok || err != nil {
+		t.Errorf("expected a bot not to act out of turn, but it did: %v", err)
+	}
+}
+
+func TestRunFullGame(t *testing.T) {
+	h := hearts.New(hearts.Options{Seed: 11})
+	h.Setup()
+
+	bots := map[int]Player{}
+
+	for seat := hearts.PlayerOne; seat <= hearts.PlayerFour; seat++ {
+		bots[seat] = NewHeuristic()
+	}
+
+	if err := Run(&h, bots); err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	if !h.Finished() {
+		t.Error("expected the bots to play the game to the end")
+	}
+
+	if len(h.Winner()) == 0 {
+		t.Error("expected the game to have a winner")
+	}
+}
+
+func checkTurn(t *testing.T, h *hearts.Hearts, expected int) {
+	t.Helper()
+	turn := h.PlayersTurn()
+
+	if len(turn) != 1 || turn[0] != expected {
+		t.Errorf("expected it to be player %d's turn, but it's %v", expected, turn)
+	}
+}
+
+func TestKinds(t *testing.T) {
+	for _, kind := range []string{KindHeuristic, KindMonteCarlo} {
+		player, err := New(kind)
+
+		if err != nil {
+			t.Fatalf("expected no error but received: %s", err)
+		}
+
+		if Kind(player) != kind {
+			t.Errorf("expected a %s bot but received a %s bot", kind, Kind(player))
+		}
+	}
+
+	if _, err := New("oracle"); err == nil {
+		t.Error("expected an unknown kind of bot to be an error")
+	}
+}
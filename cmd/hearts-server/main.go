@@ -0,0 +1,42 @@
+// Command hearts-server hosts games of Hearts over a JSON HTTP API.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/nolwn/go-hearts/server"
+	"github.com/nolwn/go-hearts/store"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	kind := flag.String("store", "memory", "where to keep games: memory, file or sqlite")
+	path := flag.String("path", "games", "directory for the file store, or database for sqlite")
+	flag.Parse()
+
+	games, err := openStore(*kind, *path)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("hosting hearts on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, server.New(games)))
+}
+
+// openStore opens the kind of store named by the -store flag.
+func openStore(kind string, path string) (store.GameStore, error) {
+	switch kind {
+	case "file":
+		return store.NewFileStore(path)
+	case "sqlite":
+		return store.NewSQLiteStore(path)
+	case "memory":
+		return store.NewMemoryStore(), nil
+	default:
+		return nil, fmt.Errorf("unknown store %q", kind)
+	}
+}
@@ -7,6 +7,11 @@ package game
 // Otherwise, methods should be reliable and should not throw errors.
 type CardGame interface {
 
+	// Apply makes a move. The game should check that the move is the kind of move that
+	// can be made at this point in the game, and that the player making it is allowed to,
+	// and return an error if not.
+	Apply(Move) error
+
 	// Finished returns true if the game has ended. An ended game should not be playable
 	// anymore.
 	Finished() bool
@@ -18,6 +23,9 @@ type CardGame interface {
 	//
 	// Play takes a player and a card which are integers. If that player cannot play,
 	// or that card cannot be played, then an error should be returned.
+	//
+	// Play is a shorthand for Apply that has to guess the kind of move being made from
+	// the phase of the game. Apply should be preferred.
 	Play(player int, card ...Card) error
 
 	// Players returns an int that represents the player whose turn it is.
@@ -0,0 +1,10 @@
+package game
+
+// Move is a single action that a player takes in a card game. Each game defines its own
+// moves, one type for each kind of action, so that what a player is trying to do is clear
+// from the type of the move rather than from how many cards they handed over.
+type Move interface {
+
+	// Player returns the index of the player making the move.
+	Player() int
+}
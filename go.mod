@@ -1,3 +1,8 @@
 module github.com/nolwn/go-hearts
 
 go 1.16
+
+require (
+	github.com/gorilla/websocket v1.5.0
+	github.com/mattn/go-sqlite3 v1.14.16
+)
@@ -1,5 +1,7 @@
 package hearts
 
+import "fmt"
+
 const (
 	SuitDiamonds = "Diamonds"
 	SuitClubs    = "Clubs"
@@ -20,6 +22,11 @@ func (c Card) Compare(other Card) int {
 	return int(c - other)
 }
 
+// String returns the name of the card, for instance "Queen of Spades".
+func (c Card) String() string {
+	return fmt.Sprintf("%s of %s", c.Value(), c.Suit())
+}
+
 // Suit returns the cards suit
 func (c Card) Suit() string {
 	if c < 13 {
@@ -54,3 +61,18 @@ func (c Card) Value() string {
 
 	return values[idx]
 }
+
+// NewCard returns the card with the given value and suit. The value and suit are written
+// the same way that Value and Suit return them (e.g. "Queen", "Spades"). An error is
+// returned if no card matches.
+func NewCard(value string, suit string) (Card, error) {
+	var c Card
+
+	for c = 0; c < 52; c++ {
+		if c.Value() == value && c.Suit() == suit {
+			return c, nil
+		}
+	}
+
+	return Nobody, fmt.Errorf("there is no card called the %s of %s", value, suit)
+}
@@ -0,0 +1,109 @@
+package hearts
+
+import "math/bits"
+
+// CardSet is a set of cards packed into the bits of an integer: the card with value n is
+// in the set if bit n is set. Since there are only 52 cards, any set of them fits in 64
+// bits, which makes sets cheap to build, copy and compare.
+//
+// The suits each take up 13 bits in the order the cards are numbered, so the cards of a
+// suit in a set can be found by masking it with the suit's mask.
+type CardSet uint64
+
+const (
+	DiamondsMask CardSet = 1<<13 - 1
+	ClubsMask    CardSet = DiamondsMask << 13
+	HeartsMask   CardSet = DiamondsMask << 26
+	SpadesMask   CardSet = DiamondsMask << 39
+
+	// FullDeck is the set of all 52 cards.
+	FullDeck CardSet = 1<<52 - 1
+)
+
+// NewCardSet returns a set holding the given cards.
+func NewCardSet(cards ...Card) CardSet {
+	var s CardSet
+
+	for _, c := range cards {
+		s |= 1 << uint(c)
+	}
+
+	return s
+}
+
+// SuitMask returns the mask for all the cards in the suit, or an empty set if the suit
+// doesn't exist.
+func SuitMask(suit string) CardSet {
+	switch suit {
+	case SuitDiamonds:
+		return DiamondsMask
+	case SuitClubs:
+		return ClubsMask
+	case SuitHearts:
+		return HeartsMask
+	case SuitSpades:
+		return SpadesMask
+	default:
+		return 0
+	}
+}
+
+// Add returns the set with the given cards added to it.
+func (s CardSet) Add(cards ...Card) CardSet {
+	return s | NewCardSet(cards...)
+}
+
+// Cards returns the cards in the set, from lowest to highest value.
+func (s CardSet) Cards() []Card {
+	cards := make([]Card, 0, s.Len())
+
+	for s != 0 {
+		c := bits.TrailingZeros64(uint64(s))
+		cards = append(cards, Card(c))
+		s &= s - 1 // clear the lowest bit
+	}
+
+	return cards
+}
+
+// Has returns true if the card is in the set.
+func (s CardSet) Has(c Card) bool {
+	return c >= 0 && c < 52 && s&(1<<uint(c)) != 0
+}
+
+// Highest returns the highest card of the suit in the set, or Nobody if there isn't one.
+func (s CardSet) Highest(suit string) Card {
+	inSuit := s.Suit(suit)
+
+	if inSuit == 0 {
+		return Nobody
+	}
+
+	return Card(bits.Len64(uint64(inSuit)) - 1)
+}
+
+// Len returns the number of cards in the set.
+func (s CardSet) Len() int {
+	return bits.OnesCount64(uint64(s))
+}
+
+// Lowest returns the lowest card of the suit in the set, or Nobody if there isn't one.
+func (s CardSet) Lowest(suit string) Card {
+	inSuit := s.Suit(suit)
+
+	if inSuit == 0 {
+		return Nobody
+	}
+
+	return Card(bits.TrailingZeros64(uint64(inSuit)))
+}
+
+// Remove returns the set without the given cards.
+func (s CardSet) Remove(cards ...Card) CardSet {
+	return s &^ NewCardSet(cards...)
+}
+
+// Suit returns the cards in the set that belong to the suit.
+func (s CardSet) Suit(suit string) CardSet {
+	return s & SuitMask(suit)
+}
@@ -0,0 +1,84 @@
+package hearts
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCardSet(t *testing.T) {
+	s := NewCardSet(0, 12, 13, 30, CardJamoke)
+
+	if s.Len() != 5 {
+		t.Errorf("expected 5 cards but found %d", s.Len())
+	}
+
+	for _, c := range []Card{0, 12, 13, 30, CardJamoke} {
+		if !s.Has(c) {
+			t.Errorf("expected the set to have the %s", c)
+		}
+	}
+
+	for _, c := range []Card{1, 26, 51, Nobody, 52} {
+		if s.Has(c) {
+			t.Errorf("expected the set not to have card %d", c)
+		}
+	}
+
+	s = s.Remove(12, 30).Add(51)
+
+	if expected := []Card{0, 13, CardJamoke, 51}; !reflect.DeepEqual(s.Cards(), expected) {
+		t.Errorf("expected %v but received %v", expected, s.Cards())
+	}
+
+	if FullDeck.Len() != 52 {
+		t.Errorf("expected a full deck to have 52 cards, but it has %d", FullDeck.Len())
+	}
+}
+
+func TestCardSetSuits(t *testing.T) {
+	for _, suit := range []string{SuitDiamonds, SuitClubs, SuitHearts, SuitSpades} {
+		mask := SuitMask(suit)
+
+		if mask.Len() != 13 {
+			t.Errorf("expected 13 %s but found %d", suit, mask.Len())
+		}
+
+		for _, c := range mask.Cards() {
+			if c.Suit() != suit {
+				t.Errorf("expected the %s mask to hold only %s, but it holds the %s", suit, suit, c)
+			}
+		}
+	}
+
+	if SuitMask("Stars") != 0 {
+		t.Error("expected a suit that doesn't exist to have an empty mask")
+	}
+
+	s := NewCardSet(3, 9, 14, 40, 45, 49)
+
+	tests := []struct {
+		suit     string
+		lowest   Card
+		highest  Card
+		suitSize int
+	}{
+		{SuitDiamonds, 3, 9, 2},
+		{SuitClubs, 14, 14, 1},
+		{SuitHearts, Nobody, Nobody, 0},
+		{SuitSpades, 40, 49, 3},
+	}
+
+	for _, test := range tests {
+		if l := s.Lowest(test.suit); l != test.lowest {
+			t.Errorf("expected the lowest of the %s to be %d but received %d", test.suit, test.lowest, l)
+		}
+
+		if h := s.Highest(test.suit); h != test.highest {
+			t.Errorf("expected the highest of the %s to be %d but received %d", test.suit, test.highest, h)
+		}
+
+		if n := s.Suit(test.suit).Len(); n != test.suitSize {
+			t.Errorf("expected %d %s but found %d", test.suitSize, test.suit, n)
+		}
+	}
+}
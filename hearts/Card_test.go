@@ -49,6 +49,32 @@ func TestCardCompare(t *testing.T) {
 	}
 }
 
+func TestCardString(t *testing.T) {
+	for _, c := range testCards {
+		if c.card.String() != c.expectedName {
+			t.Errorf("expected %s but received %s", c.expectedName, c.card.String())
+		}
+	}
+}
+
+func TestNewCard(t *testing.T) {
+	for _, c := range testCards {
+		card, err := NewCard(c.card.Value(), c.card.Suit())
+
+		if err != nil {
+			t.Errorf("expected no error but received: %s", err)
+		}
+
+		if card != c.card {
+			t.Errorf("expected %s but received %s", c.expectedName, getCardName(card))
+		}
+	}
+
+	if _, err := NewCard("Eleven", SuitHearts); err == nil {
+		t.Error("expected an error for a card that does not exist")
+	}
+}
+
 func getCardName(c Card) string {
 	return fmt.Sprintf("%s of %s", c.Value(), c.Suit())
 }
@@ -0,0 +1,77 @@
+package hearts
+
+// Clone returns a copy of the game that shares nothing with the original, so that either
+// can be played on without changing the other. The game's Shuffler is the one thing that
+// is shared; the Shufflers in this package keep no state between deals, so sharing them
+// is safe.
+func (h *Hearts) Clone() Hearts {
+	clone := *h
+
+	for i, p := range h.Players {
+		clone.Players[i] = p.clone()
+	}
+
+	// the clone gets its own table of cards, and everything that points into a table is
+	// pointed into the clone's
+	clone.cards = newCardTable()
+
+	for i, p := range clone.Players {
+		if p.Played != nil {
+			clone.Players[i].Played = clone.playedCard(*p.Played)
+		}
+	}
+
+	// events are never changed once they are logged, but the log itself is appended to
+	if h.events != nil {
+		clone.events = make([]Event, len(h.events), cap(h.events))
+		copy(clone.events, h.events)
+
+		for i, e := range clone.events {
+			if e.Card != nil {
+				clone.events[i].Card = clone.playedCard(*e.Card)
+			}
+		}
+	}
+
+	if h.scoreSheet != nil {
+		clone.scoreSheet = append([]RoundScore{}, h.scoreSheet...)
+	}
+
+	clone.tricks = cloneTricks(h.tricks)
+	clone.lastRound = cloneTricks(h.lastRound)
+
+	return clone
+}
+
+// cloneTricks copies a slice of tricks, keeping nil slices nil.
+func cloneTricks(tricks []Trick) []Trick {
+	if tricks == nil {
+		return nil
+	}
+
+	cloned := make([]Trick, len(tricks), cap(tricks))
+	copy(cloned, tricks)
+
+	return cloned
+}
+
+// clone returns a copy of the player that shares no slices or cards with the original.
+func (p Player) clone() Player {
+	p.Hand = cloneCards(p.Hand)
+	p.Taken = cloneCards(p.Taken)
+	p.Receiving = cloneCards(p.Receiving)
+
+	return p
+}
+
+// cloneCards copies a slice of cards, keeping nil slices nil.
+func cloneCards(cards []Card) []Card {
+	if cards == nil {
+		return nil
+	}
+
+	cloned := make([]Card, len(cards), cap(cards))
+	copy(cloned, cards)
+
+	return cloned
+}
@@ -0,0 +1,77 @@
+package hearts
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestClone(t *testing.T) {
+	h := midTrickGame(t)
+	h.Players[PlayerOne].Taken = []Card{4, 5, 6, 7}
+	h.Players[PlayerFour].Receiving = []Card{8, 9, 10}
+
+	clone := h.Clone()
+
+	if !reflect.DeepEqual(h, clone) {
+		t.Fatal("expected the clone to be the same as the original")
+	}
+
+	before, _ := h.MarshalJSON()
+
+	// change everything in the clone that could be shared
+	for i := range clone.Players {
+		p := &clone.Players[i]
+
+		if len(p.Hand) > 0 {
+			p.Hand[0] = CardJamoke
+		}
+
+		if p.Played != nil {
+			*p.Played = CardJamoke
+		}
+	}
+
+	clone.Players[PlayerOne].Taken[0] = CardJamoke
+	clone.Players[PlayerFour].Receiving[0] = CardJamoke
+	clone.Players[PlayerFour].Receiving = append(clone.Players[PlayerFour].Receiving, 11)
+
+	after, _ := h.MarshalJSON()
+
+	if !bytes.Equal(before, after) {
+		t.Errorf("expected the original to be unchanged, but it went from\n%s\nto\n%s", before, after)
+	}
+}
+
+func TestClonePlay(t *testing.T) {
+	h := midTrickGame(t)
+	clone := h.Clone()
+	before, _ := h.MarshalJSON()
+
+	// play the clone out to the end of the round
+	for clone.Round() == h.Round() {
+		p := clone.PlayersTurn()[0]
+
+		if err := clone.Apply(PlayMove{Seat: p, Card: clone.LegalMoves(p)[0]}); err != nil {
+			t.Fatalf("expected no error but received: %s", err)
+		}
+	}
+
+	after, _ := h.MarshalJSON()
+
+	if !bytes.Equal(before, after) {
+		t.Errorf("expected the original to be unchanged, but it went from\n%s\nto\n%s", before, after)
+	}
+
+	// and the other way around
+	clone = h.Clone()
+	p := h.PlayersTurn()[0]
+
+	if err := h.Apply(PlayMove{Seat: p, Card: h.LegalMoves(p)[0]}); err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	if len(clone.Players[p].Hand) == len(h.Players[p].Hand) || clone.Players[p].Played != nil {
+		t.Error("expected a play on the original not to show up in the clone")
+	}
+}
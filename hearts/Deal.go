@@ -0,0 +1,122 @@
+package hearts
+
+import (
+	crand "crypto/rand"
+	"math"
+	"math/big"
+	"math/rand"
+)
+
+// shufflerCrypto is how a CryptoShuffler is named when a game is stored. Games that
+// are stored without a shuffler name use a SeededShuffler.
+const shufflerCrypto = "crypto"
+
+// Deal returns the hands that are dealt at the start of the given round, before any
+// cards are passed. The deck is shuffled by the game's Shuffler and dealt one card at a
+// time around the table. With the default SeededShuffler, each round's deal is generated
+// from the game's seed, so Deal always returns the same hands for the same game and
+// round, whether that round has been played yet or not. The hands are sorted.
+func (h *Hearts) Deal(round int) [4][]Card {
+	deck := make([]Card, 52)
+	hands := [4][]Card{}
+
+	for i := range deck {
+		deck[i] = Card(i)
+	}
+
+	h.shuffler().Shuffle(deck, roundSeed(h.seed, round))
+
+	for i, card := range deck {
+		p := i % 4
+		hands[p] = append(hands[p], card)
+	}
+
+	for _, hand := range hands {
+		sort(hand, 0, len(hand)-1)
+	}
+
+	return hands
+}
+
+// Seed returns the seed that every deal in the game is generated from. A game created
+// with the same seed is dealt the same hands, round after round.
+func (h *Hearts) Seed() int64 {
+	return h.seed
+}
+
+// deal gives each player the hand they are dealt in the current round.
+func (h *Hearts) deal() {
+	hands := h.Deal(h.round)
+	rules := h.rules
+
+	for i, hand := range hands {
+		h.Players[i].Hand = hand
+	}
+
+	h.reserveEvents()
+	h.record(Event{
+		Type:     EventDeal,
+		Seat:     Nobody,
+		Cards:    dealtCards(hands),
+		Seed:     h.seed,
+		Rules:    &rules,
+		Shuffler: h.shufflerName(),
+	})
+}
+
+// shuffler returns the game's Shuffler.
+func (h *Hearts) shuffler() Shuffler {
+	if h.shuffle == nil {
+		return SeededShuffler{}
+	}
+
+	return h.shuffle
+}
+
+// shufflerName returns the name the game's Shuffler is stored under.
+func (h *Hearts) shufflerName() string {
+	switch s := h.shuffle.(type) {
+	case CryptoShuffler:
+		return shufflerCrypto
+	case *replayShuffler:
+		return s.name
+	default:
+		return ""
+	}
+}
+
+// shufflerNamed returns the Shuffler stored under the given name.
+func shufflerNamed(name string) Shuffler {
+	if name == shufflerCrypto {
+		return CryptoShuffler{}
+	}
+
+	return nil
+}
+
+// newSeed returns a seed for a game. It is drawn from the given source, or from
+// crypto/rand if there isn't one, so that nobody can work out a game's deals from when it
+// was created. It panics if the operating system's source of randomness fails.
+func newSeed(source rand.Source) int64 {
+	if source != nil {
+		return source.Int63()
+	}
+
+	seed, err := crand.Int(crand.Reader, big.NewInt(math.MaxInt64))
+
+	if err != nil {
+		panic("hearts: unable to read random numbers: " + err.Error())
+	}
+
+	return seed.Int64()
+}
+
+// roundSeed mixes the game's seed with the round number, so that each round is dealt
+// from its own seed, and games whose seeds are close together don't share deals.
+func roundSeed(seed int64, round int) int64 {
+	z := uint64(seed) + uint64(round)*0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+
+	return int64(z ^ (z >> 31))
+}
@@ -0,0 +1,90 @@
+package hearts
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestSeededDeal(t *testing.T) {
+	first := New(Options{Seed: 42})
+	second := New(Options{Seed: 42})
+	other := New(Options{Seed: 43})
+
+	first.Setup()
+	second.Setup()
+	other.Setup()
+
+	if first.Seed() != 42 {
+		t.Errorf("expected seed 42 but received %d", first.Seed())
+	}
+
+	if !reflect.DeepEqual(hands(first), hands(second)) {
+		t.Error("expected games with the same seed to be dealt the same hands")
+	}
+
+	if reflect.DeepEqual(hands(first), hands(other)) {
+		t.Error("expected games with different seeds to be dealt different hands")
+	}
+
+	if !checkHandsAreSorted(&first) {
+		t.Error("expected dealt hands to be sorted, but they were not")
+	}
+}
+
+func TestSourceDeal(t *testing.T) {
+	first := New(Options{Source: rand.NewSource(7)})
+	second := New(Options{Source: rand.NewSource(7)})
+
+	if first.Seed() != second.Seed() {
+		t.Errorf("expected seeds from the same source to match: %d, %d", first.Seed(), second.Seed())
+	}
+}
+
+func TestDefaultSeed(t *testing.T) {
+	first := New(Options{})
+	second := New(Options{})
+
+	if first.Seed() == second.Seed() {
+		t.Errorf("expected games without a seed to be given different seeds, but both have %d", first.Seed())
+	}
+}
+
+func TestDealRegenerated(t *testing.T) {
+	h := New(Options{Seed: 1234})
+	h.Setup()
+
+	if !reflect.DeepEqual(hands(h), h.Deal(1)) {
+		t.Error("expected the first round to be dealt the hands from Deal(1)")
+	}
+
+	// finish off round 1 without playing it
+	for i := range h.Players {
+		h.Players[i].Hand = []Card{}
+	}
+
+	h.phase = PhasePlay
+	h.phaseEnd = true
+	h.NextPhase()
+
+	if h.Round() != 2 {
+		t.Fatalf("expected round 2 but it's round %d", h.Round())
+	}
+
+	if !reflect.DeepEqual(hands(h), h.Deal(2)) {
+		t.Error("expected the second round to be dealt the hands from Deal(2)")
+	}
+
+	if reflect.DeepEqual(h.Deal(1), h.Deal(2)) {
+		t.Error("expected each round to be dealt different hands")
+	}
+}
+
+func hands(h Hearts) [4][]Card {
+	return [4][]Card{
+		h.Players[PlayerOne].Hand,
+		h.Players[PlayerTwo].Hand,
+		h.Players[PlayerThree].Hand,
+		h.Players[PlayerFour].Hand,
+	}
+}
@@ -0,0 +1,94 @@
+package hearts
+
+import "fmt"
+
+// ErrorCode is a stable, machine-readable name for a rule that a move broke. Codes never
+// change once they are released, so clients can rely on them even if the wording of an
+// error does.
+type ErrorCode string
+
+const (
+	CodeCardNotInHand        ErrorCode = "CardNotInHand"
+	CodeDuplicateCard        ErrorCode = "DuplicateCard"
+	CodeGameFinished         ErrorCode = "GameFinished"
+	CodeHeartsNotBroken      ErrorCode = "HeartsNotBroken"
+	CodeMustFollowSuit       ErrorCode = "MustFollowSuit"
+	CodeMustPlayTwoOfClubs   ErrorCode = "MustPlayTwoOfClubs"
+	CodeNoPassOnHold         ErrorCode = "NoPassOnHold"
+	CodeNoPointsOnFirstTrick ErrorCode = "NoPointsOnFirstTrick"
+	CodeNoTakebacks          ErrorCode = "NoTakebacks"
+	CodeNotYourMove          ErrorCode = "NotYourMove"
+	CodeNotYourTurn          ErrorCode = "NotYourTurn"
+	CodeNothingToUndo        ErrorCode = "NothingToUndo"
+	CodeUndoNeedsAgreement   ErrorCode = "UndoNeedsAgreement"
+	CodeUnknownMove          ErrorCode = "UnknownMove"
+	CodeWrongPassCount       ErrorCode = "WrongPassCount"
+	CodeWrongPhase           ErrorCode = "WrongPhase"
+	CodeWrongPlayCount       ErrorCode = "WrongPlayCount"
+)
+
+// These errors can be compared against any error returned by Apply, Play or Undo with
+// errors.Is. Two RuleErrors match if they have the same Code, no matter which player or
+// cards they are about.
+var (
+	ErrCardNotInHand        = &RuleError{Code: CodeCardNotInHand, Player: Nobody}
+	ErrDuplicateCard        = &RuleError{Code: CodeDuplicateCard, Player: Nobody}
+	ErrGameFinished         = &RuleError{Code: CodeGameFinished, Player: Nobody}
+	ErrHeartsNotBroken      = &RuleError{Code: CodeHeartsNotBroken, Player: Nobody}
+	ErrMustFollowSuit       = &RuleError{Code: CodeMustFollowSuit, Player: Nobody}
+	ErrMustPlayTwoOfClubs   = &RuleError{Code: CodeMustPlayTwoOfClubs, Player: Nobody}
+	ErrNoPassOnHold         = &RuleError{Code: CodeNoPassOnHold, Player: Nobody}
+	ErrNoPointsOnFirstTrick = &RuleError{Code: CodeNoPointsOnFirstTrick, Player: Nobody}
+	ErrNoTakebacks          = &RuleError{Code: CodeNoTakebacks, Player: Nobody}
+	ErrNotYourMove          = &RuleError{Code: CodeNotYourMove, Player: Nobody}
+	ErrNotYourTurn          = &RuleError{Code: CodeNotYourTurn, Player: Nobody}
+	ErrNothingToUndo        = &RuleError{Code: CodeNothingToUndo, Player: Nobody}
+	ErrUndoNeedsAgreement   = &RuleError{Code: CodeUndoNeedsAgreement, Player: Nobody}
+	ErrUnknownMove          = &RuleError{Code: CodeUnknownMove, Player: Nobody}
+	ErrWrongPassCount       = &RuleError{Code: CodeWrongPassCount, Player: Nobody}
+	ErrWrongPhase           = &RuleError{Code: CodeWrongPhase, Player: Nobody}
+	ErrWrongPlayCount       = &RuleError{Code: CodeWrongPlayCount, Player: Nobody}
+)
+
+// RuleError is returned when a move can't be made, either because it breaks the rules of
+// Hearts or because the game isn't in a state where the move makes sense.
+type RuleError struct {
+
+	// Code names the rule that was broken.
+	Code ErrorCode
+
+	// Player is the index of the player who made the move, or Nobody.
+	Player int
+
+	// Cards are the cards that broke the rule, if any.
+	Cards []Card
+
+	// message explains what went wrong in words.
+	message string
+}
+
+// Error returns a description of the broken rule.
+func (e *RuleError) Error() string {
+	if e.message == "" {
+		return string(e.Code)
+	}
+
+	return e.message
+}
+
+// Is reports whether target is a RuleError with the same Code.
+func (e *RuleError) Is(target error) bool {
+	t, ok := target.(*RuleError)
+
+	return ok && t.Code == e.Code
+}
+
+// ruleError returns a RuleError for a player. The message is formatted with fmt.Sprintf.
+func ruleError(code ErrorCode, player int, cards []Card, format string, a ...interface{}) error {
+	return &RuleError{
+		Code:    code,
+		Player:  player,
+		Cards:   append([]Card{}, cards...),
+		message: fmt.Sprintf(format, a...),
+	}
+}
@@ -0,0 +1,114 @@
+package hearts
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRuleErrors(t *testing.T) {
+	h := setupCannedHands(handFull)
+	hand := h.Players[PlayerOne].Hand
+
+	checkRuleError(t, h.Play(PlayerOne, hand[0], hand[1]), ErrWrongPassCount, PlayerOne)
+	checkRuleError(t, h.Play(PlayerOne, hand[0], hand[0], hand[1]), ErrDuplicateCard, PlayerOne, hand[0])
+	checkRuleError(t, h.Play(PlayerOne, hand[0], hand[1], 51), ErrCardNotInHand, PlayerOne, 51)
+	checkRuleError(t, h.Apply(PlayMove{Seat: PlayerOne, Card: hand[0]}), ErrWrongPhase, PlayerOne, hand[0])
+
+	h.round = 4
+	checkRuleError(t, h.Play(PlayerOne, hand[0], hand[1], hand[2]), ErrNoPassOnHold, PlayerOne)
+
+	// Player two has the Two of Clubs, so they lead
+	h.phase = PhasePlay
+	checkRuleError(t, h.Play(PlayerOne, hand[0]), ErrNotYourTurn, PlayerOne)
+	checkRuleError(t, h.Play(PlayerOne, hand[0], hand[1]), ErrNotYourTurn, PlayerOne)
+	checkRuleError(t, h.Play(PlayerTwo, 5, 9), ErrWrongPlayCount, PlayerTwo)
+	checkRuleError(t, h.Play(PlayerTwo, 5), ErrMustPlayTwoOfClubs, PlayerTwo, 5)
+	checkRuleError(t, h.Play(PlayerTwo, 0), ErrCardNotInHand, PlayerTwo, 0)
+	play(t, &h, PlayerTwo, false, CardTwoOfClubs)
+	checkRuleError(t, h.Play(PlayerOne, 28), ErrMustFollowSuit, PlayerOne, 28)
+
+	h = setupCannedHands(handSmall)
+	h.phase = PhasePlay
+	h.lastTaken = PlayerFour
+	checkRuleError(t, h.Play(PlayerFour, 27), ErrHeartsNotBroken, PlayerFour, 27)
+
+	play(t, &h, PlayerFour, false, 19)
+	play(t, &h, PlayerThree, false, 18)
+	checkRuleError(t, h.Play(PlayerTwo, 29), ErrNoPointsOnFirstTrick, PlayerTwo, 29)
+
+	h.finished = true
+	checkRuleError(t, h.Play(PlayerTwo, 1), ErrGameFinished, PlayerTwo)
+	checkRuleError(t, h.Play(PlayerTwo, 1, 5), ErrGameFinished, PlayerTwo)
+}
+
+func TestRuleErrorIs(t *testing.T) {
+	err := ruleError(CodeMustFollowSuit, PlayerThree, []Card{CardJamoke}, "must follow suit")
+
+	if !errors.Is(err, ErrMustFollowSuit) {
+		t.Error("expected the error to match ErrMustFollowSuit")
+	}
+
+	if errors.Is(err, ErrHeartsNotBroken) {
+		t.Error("expected the error not to match ErrHeartsNotBroken")
+	}
+
+	if err.Error() != "must follow suit" {
+		t.Errorf("expected the message \"must follow suit\" but received %q", err.Error())
+	}
+
+	// players are described by id, not index
+	h := setupCannedHands(handFull)
+	h.phase = PhasePlay
+
+	if err := h.Play(PlayerOne, 0); err == nil || err.Error() != "it is not player 1's turn" {
+		t.Errorf("expected player 1 to be told it isn't their turn, but received %v", err)
+	}
+
+	if err := h.Play(PlayerTwo, 0); err == nil || err.Error() != "player 2 does not have the "+Card(0).String() {
+		t.Errorf("expected player 2 to be told they don't have the card, but received %v", err)
+	}
+
+	if ErrNotYourTurn.Error() != string(CodeNotYourTurn) {
+		t.Errorf("expected a sentinel to describe itself by its code, not %q", ErrNotYourTurn.Error())
+	}
+}
+
+// checkRuleError fails the test if err is not a RuleError matching the expected error, or
+// if it isn't about the given player and cards.
+func checkRuleError(t *testing.T, err error, expected error, player int, cards ...Card) {
+	t.Helper()
+
+	if !errors.Is(err, expected) {
+		t.Errorf("expected %s but received: %v", expected, err)
+		return
+	}
+
+	var re *RuleError
+
+	if !errors.As(err, &re) {
+		t.Errorf("expected a *RuleError but received %T", err)
+		return
+	}
+
+	if re.Player != player {
+		t.Errorf("expected the error to be about player %d, not %d", player, re.Player)
+	}
+
+	if len(cards) > 0 && !compareCards(re.Cards, cards) {
+		t.Errorf("expected the error to be about %v, not %v", cards, re.Cards)
+	}
+}
+
+func compareCards(first []Card, second []Card) bool {
+	if len(first) != len(second) {
+		return false
+	}
+
+	for i := range first {
+		if first[i] != second[i] {
+			return false
+		}
+	}
+
+	return true
+}
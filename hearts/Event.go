@@ -0,0 +1,270 @@
+package hearts
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// eventsPerRound is more events than a round ever logs. Room for a whole round is kept in
+// the log at each deal so that playing cards doesn't allocate.
+const eventsPerRound = 100
+
+// EventType names something that happened in a game.
+type EventType string
+
+const (
+
+	// EventDeal is logged when a round's hands are dealt. Cards holds all 52 cards, one
+	// hand of 13 after the other in seat order. The deal also records the game's Seed,
+	// Rules and Shuffler so that the game can be rebuilt from its log.
+	EventDeal EventType = "deal"
+
+	// EventPass is logged when the player in Seat picks the Cards they are passing.
+	EventPass EventType = "pass"
+
+	// EventPassesResolved is logged when every player has passed and the passed cards
+	// have been added to the hands they were passed to.
+	EventPassesResolved EventType = "passesResolved"
+
+	// EventPlay is logged when the player in Seat plays Card into the Trick.
+	EventPlay EventType = "play"
+
+	// EventTrickLed is logged right after the play in which the player in Seat led the
+	// Trick with Card, which sets the Suit the others have to follow.
+	EventTrickLed EventType = "trickLed"
+
+	// EventHeartsBroken is logged right after the play in which the player in Seat broke
+	// hearts with Card.
+	EventHeartsBroken EventType = "heartsBroken"
+
+	// EventTrickTaken is logged when the trick is resolved and the player in Seat takes
+	// the Trick, along with the Points in it. The next trick has no led suit until it is
+	// led.
+	EventTrickTaken EventType = "trickTaken"
+
+	// EventRoundScored is logged at the end of a round. RoundPoints are the points each
+	// seat took in the round and Scores are each seat's game score afterward. If a player
+	// shot the moon, they are in Seat.
+	EventRoundScored EventType = "roundScored"
+
+	// EventGameFinished is logged when the game ends, with the Winners.
+	EventGameFinished EventType = "gameFinished"
+)
+
+// Event is one thing that happened in a game. Only the fields that matter to the Type of
+// the event are set. Seats are player indices, and Seat is Nobody when the event isn't
+// about one seat.
+type Event struct {
+	Type        EventType `json:"type"`
+	Round       int       `json:"round"`
+	Trick       int       `json:"trick,omitempty"`
+	Seat        int       `json:"seat"`
+	Card        *Card     `json:"card,omitempty"`
+	Cards       []Card    `json:"cards,omitempty"`
+	Suit        string    `json:"suit,omitempty"`
+	Points      int       `json:"points,omitempty"`
+	RoundPoints []int     `json:"roundPoints,omitempty"`
+	Scores      []int     `json:"scores,omitempty"`
+	Winners     []int     `json:"winners,omitempty"`
+	Seed        int64     `json:"seed,omitempty"`
+	Rules       *Rules    `json:"rules,omitempty"`
+	Shuffler    string    `json:"shuffler,omitempty"`
+}
+
+// Events returns everything that has happened in the game, in the order it happened. A
+// game created with FromPosition starts its log in the middle of a round, and games
+// stored before events were logged start theirs wherever they were restored. The events
+// are copies, so changing them doesn't change the game.
+func (h *Hearts) Events() []Event {
+	events := make([]Event, 0, len(h.events))
+
+	for _, e := range h.events {
+		events = append(events, e.clone())
+	}
+
+	return events
+}
+
+// clone returns a copy of the event that shares no cards, slices or rules with the
+// original.
+func (e Event) clone() Event {
+	if e.Card != nil {
+		card := *e.Card
+		e.Card = &card
+	}
+
+	if e.Rules != nil {
+		rules := *e.Rules
+		e.Rules = &rules
+	}
+
+	e.Cards = cloneCards(e.Cards)
+	e.RoundPoints = cloneInts(e.RoundPoints)
+	e.Scores = cloneInts(e.Scores)
+	e.Winners = cloneInts(e.Winners)
+
+	return e
+}
+
+// cloneInts copies a slice of ints, keeping nil slices nil.
+func cloneInts(ints []int) []int {
+	if ints == nil {
+		return nil
+	}
+
+	return append([]int{}, ints...)
+}
+
+// Replay rebuilds a game from its events. The passes and plays are made again with the
+// rules of the game, and everything that follows from them has to match what was logged.
+// An error is returned if a move breaks the rules or if the game doesn't turn out the way
+// the log says it did. The deals are taken from the log, so even games dealt with a
+// CryptoShuffler can be replayed.
+func Replay(events []Event) (Hearts, error) {
+	if !startsWithDeal(events) {
+		return Hearts{}, errors.New("a game's log must start with a deal")
+	}
+
+	first := events[0]
+	shuffler := &replayShuffler{name: first.Shuffler}
+
+	for i, e := range events {
+		if e.Type != EventDeal {
+			continue
+		}
+
+		if len(e.Cards) != 52 || NewCardSet(e.Cards...) != FullDeck {
+			return Hearts{}, fmt.Errorf("event %d: a deal must hand out the whole deck", i)
+		}
+
+		shuffler.deals = append(shuffler.deals, e.Cards)
+	}
+
+	opts := Options{Seed: first.Seed, Shuffler: shuffler}
+
+	if first.Rules != nil {
+		opts.Rules = *first.Rules
+	}
+
+	h := New(opts)
+	h.round = first.Round
+
+	if err := h.Setup(); err != nil {
+		return h, err
+	}
+
+	for i, e := range events[1:] {
+		var err error
+
+		switch e.Type {
+		case EventPass:
+			err = h.Apply(PassMove{Seat: e.Seat, Cards: e.Cards})
+		case EventPlay:
+			if e.Card == nil {
+				return h, fmt.Errorf("event %d: a play must have a card", i+1)
+			}
+
+			err = h.Apply(PlayMove{Seat: e.Seat, Card: *e.Card})
+		}
+
+		if err != nil {
+			return h, fmt.Errorf("event %d: %w", i+1, err)
+		}
+	}
+
+	h.shuffle = shufflerNamed(first.Shuffler)
+
+	if i := firstDifference(events, h.events); i != Nobody {
+		return h, fmt.Errorf("event %d: the log doesn't match the game", i)
+	}
+
+	return h, nil
+}
+
+// partOfPlay returns true for the events that are logged along with a play to say what it
+// changed, rather than for anything the play completed.
+func (t EventType) partOfPlay() bool {
+	return t == EventTrickLed || t == EventHeartsBroken
+}
+
+// record adds an event to the game's log.
+func (h *Hearts) record(e Event) {
+	e.Round = h.round
+	h.events = append(h.events, e)
+}
+
+// reserveEvents makes sure there is room in the log for another round.
+func (h *Hearts) reserveEvents() {
+	if cap(h.events)-len(h.events) >= eventsPerRound {
+		return
+	}
+
+	events := make([]Event, len(h.events), 2*len(h.events)+eventsPerRound)
+	copy(events, h.events)
+	h.events = events
+}
+
+// firstDifference returns the index of the first event that differs between the logs, or
+// Nobody if they are the same.
+func firstDifference(expected []Event, actual []Event) int {
+	for i := range expected {
+		if i >= len(actual) || !reflect.DeepEqual(expected[i], actual[i]) {
+			return i
+		}
+	}
+
+	if len(actual) > len(expected) {
+		return len(expected)
+	}
+
+	return Nobody
+}
+
+// replayShuffler deals the hands recorded in a game's log, in the order they were logged.
+// Once it runs out of logged deals, it shuffles the way the game's Shuffler would.
+type replayShuffler struct {
+	deals [][]Card
+	name  string
+	next  int
+}
+
+// Shuffle arranges the deck so that dealing it around the table gives each seat the hand
+// it was logged with.
+func (s *replayShuffler) Shuffle(deck []Card, seed int64) {
+	if s.next >= len(s.deals) {
+		shuffler := shufflerNamed(s.name)
+
+		if shuffler == nil {
+			shuffler = SeededShuffler{}
+		}
+
+		shuffler.Shuffle(deck, seed)
+
+		return
+	}
+
+	cards := s.deals[s.next]
+	s.next++
+
+	for i := range deck {
+		deck[i] = cards[(i%4)*13+i/4]
+	}
+}
+
+// dealtCards returns every hand, one after the other in seat order.
+func dealtCards(hands [4][]Card) []Card {
+	cards := make([]Card, 0, 52)
+
+	for _, hand := range hands {
+		cards = append(cards, hand...)
+	}
+
+	return cards
+}
+
+// startsWithDeal returns true if the events start with a deal, which every log that can
+// be replayed does.
+func startsWithDeal(events []Event) bool {
+	return len(events) > 0 && events[0].Type == EventDeal
+}
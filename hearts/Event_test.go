@@ -0,0 +1,233 @@
+package hearts
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestEvents(t *testing.T) {
+	h := New(Options{Seed: 7})
+	h.Setup()
+	playRounds(t, &h, 1)
+
+	events := h.Events()
+	counts := map[EventType]int{}
+
+	for _, e := range events {
+		counts[e.Type]++
+	}
+
+	expected := map[EventType]int{
+		EventDeal:           2, // the first round's and the second's
+		EventPass:           4,
+		EventPassesResolved: 1,
+		EventPlay:           52,
+		EventTrickTaken:     13,
+		EventRoundScored:    1,
+	}
+
+	for eventType, n := range expected {
+		if counts[eventType] != n {
+			t.Errorf("expected %d %s events but found %d", n, eventType, counts[eventType])
+		}
+	}
+
+	if events[0].Type != EventDeal || events[0].Seed != 7 || len(events[0].Cards) != 52 {
+		t.Errorf("expected the log to start with the deal, but it starts with %+v", events[0])
+	}
+
+	// the passes come before they are resolved, and the plays follow
+	for i, eventType := range []EventType{EventPass, EventPass, EventPass, EventPass, EventPassesResolved, EventPlay} {
+		if events[i+1].Type != eventType {
+			t.Errorf("expected event %d to be %s but it was %s", i+1, eventType, events[i+1].Type)
+		}
+	}
+
+	// every fourth play takes a trick
+	plays := 0
+
+	for i, e := range events {
+		switch e.Type {
+		case EventPlay:
+			plays++
+		case EventTrickTaken:
+			if plays != 4 {
+				t.Fatalf("expected event %d to take a trick of 4 plays, but the trick had %d", i, plays)
+			}
+
+			plays = 0
+		}
+	}
+
+	// every trick is led once, and hearts are broken at most once, each right after the
+	// play that did it
+	if counts[EventTrickLed] != counts[EventTrickTaken] {
+		t.Errorf("expected %d tricks to be led but %d were", counts[EventTrickTaken], counts[EventTrickLed])
+	}
+
+	var lastPlay Event
+
+	for _, e := range events {
+		switch e.Type {
+		case EventPlay:
+			lastPlay = e
+		case EventTrickLed, EventHeartsBroken:
+			if *lastPlay.Card != *e.Card || (e.Type == EventHeartsBroken && counts[e.Type] != 1) {
+				t.Errorf("expected %s to follow the play that caused it, but found %+v", e.Type, e)
+			}
+		}
+	}
+
+	scored := events[len(events)-2]
+	points := 0
+
+	for _, p := range scored.RoundPoints {
+		points += p
+	}
+
+	if scored.Type != EventRoundScored || points != MoonPoints {
+		t.Errorf("expected the round to be scored with 26 points, but found %+v", scored)
+	}
+
+	if last := events[len(events)-1]; last.Type != EventDeal || last.Round != 2 {
+		t.Errorf("expected the log to end with the second round's deal, but it ends with %+v", last)
+	}
+}
+
+func TestEventsGameFinished(t *testing.T) {
+	h := New(Options{Seed: 3, Rules: Rules{TargetScore: 20}})
+	h.Setup()
+	playRounds(t, &h, 10)
+
+	var finished *Event
+
+	for _, e := range h.Events() {
+		if e.Type == EventGameFinished {
+			e := e
+			finished = &e
+		}
+	}
+
+	if finished == nil || len(finished.Winners) == 0 {
+		t.Fatalf("expected the game to finish with a winner, but it finished with %+v", finished)
+	}
+}
+
+func TestEventsCopied(t *testing.T) {
+	h := New(Options{Seed: 3})
+	h.Setup()
+	playRounds(t, &h, 1)
+
+	other := New(Options{Seed: 3})
+	other.Setup()
+	playRounds(t, &other, 1)
+
+	before, _ := h.MarshalJSON()
+	otherBefore, _ := other.MarshalJSON()
+
+	// change every card in the events that were handed out
+	for _, e := range h.Events() {
+		if e.Card != nil {
+			*e.Card = CardJamoke
+		}
+
+		for i := range e.Cards {
+			e.Cards[i] = CardJamoke
+		}
+	}
+
+	if after, _ := h.MarshalJSON(); !bytes.Equal(before, after) {
+		t.Error("expected changing the events not to change the game")
+	}
+
+	if after, _ := other.MarshalJSON(); !bytes.Equal(otherBefore, after) {
+		t.Error("expected changing the events not to change another game")
+	}
+}
+
+func TestReplay(t *testing.T) {
+	for _, shuffler := range []Shuffler{nil, CryptoShuffler{}} {
+		h := New(Options{Seed: 5, Shuffler: shuffler})
+		h.Setup()
+		playRounds(t, &h, 2)
+
+		// send the log through JSON, as it would be stored
+		b, err := json.Marshal(h.Events())
+
+		if err != nil {
+			t.Fatalf("expected no error but received: %s", err)
+		}
+
+		var events []Event
+
+		if err := json.Unmarshal(b, &events); err != nil {
+			t.Fatalf("expected no error but received: %s", err)
+		}
+
+		replayed, err := Replay(events)
+
+		if err != nil {
+			t.Fatalf("expected no error but received: %s", err)
+		}
+
+		checkSameState(t, &h, &replayed)
+	}
+}
+
+func TestReplayErrors(t *testing.T) {
+	h := New(Options{Seed: 5})
+	h.Setup()
+	playRounds(t, &h, 1)
+	events := h.Events()
+
+	if _, err := Replay(events[1:]); err == nil {
+		t.Error("expected an error for a log that doesn't start with a deal")
+	}
+
+	// someone claims another player took the first trick
+	tampered := append([]Event{}, events...)
+	taken := tampered[10]
+	taken.Seat = (taken.Seat + 1) % 4
+	tampered[10] = taken
+
+	if _, err := Replay(tampered); err == nil {
+		t.Error("expected an error for a log with the wrong trick winner")
+	}
+
+	// a play made out of turn
+	tampered = append([]Event{}, events...)
+	play := tampered[6]
+	play.Seat = (play.Seat + 1) % 4
+	tampered[6] = play
+
+	if _, err := Replay(tampered); !errors.Is(err, ErrNotYourTurn) {
+		t.Errorf("expected a %s error but received: %v", CodeNotYourTurn, err)
+	}
+
+	// a log that stops short of what its moves lead to
+	if _, err := Replay(events[:len(events)-1]); err == nil {
+		t.Error("expected an error for a log missing the next deal")
+	}
+}
+
+// playRounds plays the given number of rounds, or until the game ends. Each player passes
+// their first three cards and plays their first legal card.
+func playRounds(t *testing.T, h *Hearts, rounds int) {
+	t.Helper()
+	last := h.Round() + rounds
+
+	for !h.Finished() && h.Round() < last {
+		p := h.PlayersTurn()[0]
+		cards := h.LegalMoves(p)[:1]
+
+		if h.Phase() == PhasePass {
+			cards = h.LegalMoves(p)[:3]
+		}
+
+		if err := h.Play(p, cards...); err != nil {
+			t.Fatalf("expected no error but received: %s", err)
+		}
+	}
+}
@@ -1,11 +1,6 @@
 package hearts
 
-import (
-	"errors"
-	"fmt"
-	"math/rand"
-	"time"
-)
+import "errors"
 
 // The Two of Clubs is represented by the integer 13
 const CardTwoOfClubs Card = 13
@@ -28,32 +23,60 @@ func (h *Hearts) Finished() bool {
 
 // Play in Hearts means one of two things depending on the phase. In the pass
 // phase, players pick three cards to pass. In the play phase, players pick one card
-// to play into trick.
+// to play into trick. Play makes a PassMove or a PlayMove from the cards, depending on
+// the phase, and applies it.
 //
-// An error will be returned if it isn't the players turn to play.
+// A *RuleError will be returned if it isn't the players turn to play, or if the cards
+// can't be played. Whose turn it is gets checked before how many cards were given.
 func (h *Hearts) Play(player int, cards ...Card) error {
-	if h.finished {
-		return errors.New("the game is finished")
+	if h.Phase() == PhasePass {
+		return h.pass(PassMove{Seat: player, Cards: cards})
 	}
 
-	playing := h.PlayersTurn()
-	canPlay := false
+	if err := h.checkTurn(player); err != nil {
+		return err
+	}
 
-	for _, p := range playing {
-		if p == player {
-			canPlay = true
-		}
+	if h.phase != PhasePlay {
+		return ruleError(
+			CodeWrongPhase,
+			player,
+			cards,
+			"cards can only be played during the play phase",
+		)
 	}
 
-	if !canPlay {
-		return fmt.Errorf("it is not player %d's turn", player)
+	if len(cards) != 1 {
+		return ruleError(
+			CodeWrongPlayCount,
+			player,
+			cards,
+			"player must play exactly one card",
+		)
 	}
 
-	if h.Phase() == PhasePlay {
-		return h.playPhase(player, cards...)
-	} else {
-		return h.passPhase(player, cards...)
+	return h.play(PlayMove{Seat: player, Card: cards[0]})
+}
+
+// LegalMoves returns the cards that the player is allowed to play right now. During the
+// pass phase, that is any card in their hand. During the play phase, it is the cards that
+// follow the rules for the current trick. If it isn't the player's turn, or the game is
+// finished, no cards are returned. The only thing it allocates is the slice it returns.
+func (h *Hearts) LegalMoves(player int) []Card {
+	if h.finished || !h.isTurn(player) {
+		return []Card{}
 	}
+
+	hand := h.Players[player].Hand
+	legal := make([]Card, 0, len(hand))
+
+	for _, card := range hand {
+		if h.phase == PhasePass || h.brokenRule(player, card) == "" {
+			legal = append(legal, card)
+		}
+	}
+
+	return legal
 }
 
 // Player returns the index of the players who are allowed to take a turn. During the
@@ -69,8 +92,25 @@ func (h *Hearts) PlayersTurn() []int {
 	}
 }
 
-// Setup sets up a new Hearts round. It deals out 13 cards randomly to each player. It
-// also clears our each player's Taken slice.
+// PassTarget returns the index of the player that the given player passes to in the given
+// round: to the left (their index -1) in the first round, to the right (their index +1) in
+// the second, across the table in the third, and to Nobody in the fourth, when cards are
+// held. The pattern repeats every four rounds.
+func PassTarget(round int, player int) int {
+	switch round % 4 {
+	case 1:
+		return (player + 3) % 4
+	case 2:
+		return (player + 1) % 4
+	case 3:
+		return (player + 2) % 4
+	default:
+		return Nobody
+	}
+}
+
+// Setup sets up a new Hearts round. It deals out 13 cards to each player, as Deal would
+// for the current round, and sorts them. It also clears our each player's Taken slice.
 func (h *Hearts) Setup() error {
 	cards := 0
 
@@ -87,10 +127,6 @@ func (h *Hearts) Setup() error {
 	h.lastTaken = -1
 	h.finished = false
 
-	for _, p := range h.Players {
-		sort(p.Hand, 0, len(p.Hand)-1)
-	}
-
 	return nil
 }
 
@@ -121,110 +157,69 @@ func (h *Hearts) Winner() (winners []int) {
 	return
 }
 
-// clearTaken clears out any tricks taken by each of the players
+// clearTaken clears out any tricks taken by each of the players. The tricks of the round
+// that is over are kept for review until the next round is over.
 func (h *Hearts) clearTaken() {
-	for _, p := range h.Players {
-		p.Taken = make([]Card, 0, 13)
-	}
-}
-
-func (h *Hearts) deal() {
-	var n Card = 0 // cards in a deck, starting with 0
-	i := PlayerOne // player index
-	deck := make([]Card, 0, 52)
-
-	for n < 52 {
-		deck = append(deck, n)
-		n++
-	}
-
-	n-- // the loop above stopped once n was 1 over the max. Need to bump it back down 1
-
-	seed := time.Now().UnixNano()
-	r := rand.New(rand.NewSource(seed))
-
-	for n > 0 {
-		ri := r.Intn(int(n))
-		card := deck[ri]
-
-		// overwrite the selected card with the one that will be removed when we shorten
-		// the deck
-		deck[ri] = deck[n]
-		n--
-
-		h.Players[i].Hand = append(h.Players[i].Hand, card)
-
-		if i == PlayerFour {
-			i = PlayerOne
-		} else {
-			i++
-		}
+	for i := range h.Players {
+		h.Players[i].Taken = h.Players[i].Taken[:0]
 	}
 
-	// the loop ends one card early because we can't generate a random number between 0
-	// and 0. We just need to scoop up that last card.
-	h.Players[i].Hand = append(h.Players[i].Hand, deck[0])
-}
+	h.lastRound, h.tricks = h.tricks, h.lastRound[:0]
 
-// passAcross returns the target across the table
-func (h *Hearts) passAcross(player int, cards []Card) int {
-	switch player {
-	case PlayerOne:
-		return PlayerThree
-	case PlayerTwo:
-		return PlayerFour
-	case PlayerThree:
-		return PlayerOne
-	default: // case PlayerFour:
-		return PlayerTwo
-	}
-}
-
-// passLeft returns the target to the players left (toward begining of array)
-func (h *Hearts) passLeft(player int, cards []Card) int {
-	if player == PlayerOne {
-		return PlayerFour
-	} else {
-		return player - 1
+	if cap(h.tricks) < tricksPerRound {
+		h.tricks = make([]Trick, 0, tricksPerRound)
 	}
 }
 
 // passPhase contains the logic for playing a card during the pass phase.
 func (h *Hearts) passPhase(player int, cards ...Card) error {
-	pass := h.Round() % 4
 	playerHand := &h.Players[player].Hand
-	var target int
+	target := PassTarget(h.round, player)
 
 	if len(cards) != 3 {
-		return errors.New("player must pass exactly 3 cards")
+		return ruleError(
+			CodeWrongPassCount,
+			player,
+			cards,
+			"player must pass exactly 3 cards, not %d",
+			len(cards),
+		)
 	}
 
 	for i, c := range cards {
 		for j := i + 1; j < len(cards); j++ {
 			if c == cards[j] {
-				return errors.New("player must play 3 different cards")
+				return ruleError(
+					CodeDuplicateCard,
+					player,
+					[]Card{c},
+					"player must pass 3 different cards, but passed the %s twice",
+					c,
+				)
 			}
 		}
 	}
 
 	if !hasCard(*playerHand, cards...) {
-		return errors.New("player must have the cards to pass them")
+		return ruleError(
+			CodeCardNotInHand,
+			player,
+			missingCards(*playerHand, cards),
+			"player must have the cards to pass them",
+		)
 	}
 
-	switch pass {
-	case 0:
-		return errors.New("player cannot pass on the hold round")
-	case 1:
-		target = h.passLeft(player, cards)
-	case 2:
-		target = h.passRight(player, cards)
-	case 3:
-		target = h.passAcross(player, cards)
+	if target == Nobody {
+		return ruleError(CodeNoPassOnHold, player, cards, "player cannot pass on the hold round")
 	}
 
-	*playerHand = removeCard(*playerHand, cards...)
+	// the cards may share an array with the hand, so they are copied before it changes
+	passed := append([]Card{}, cards...)
+
+	*playerHand = removeCard(*playerHand, passed...)
 	h.Players[player].hasPassed = true
-	h.Players[target].Receiving = cards
+	h.Players[target].Receiving = passed
+	h.record(Event{Type: EventPass, Seat: player, Cards: append([]Card{}, passed...)})
 
 	playing := h.PlayersTurn()
 
@@ -240,6 +235,19 @@ func (h *Hearts) passPhase(player int, cards ...Card) error {
 	return nil
 }
 
+// isTurn returns true if the player is one of the players allowed to take a turn.
+func (h *Hearts) isTurn(player int) bool {
+	if player < PlayerOne || player > PlayerFour {
+		return false
+	}
+
+	if h.phase == PhasePass {
+		return !h.Players[player].hasPassed
+	}
+
+	return h.currentPlayer() == player
+}
+
 // currentlyPassing returns the players who have not yet picked cards to pass
 func (h *Hearts) currentlyPassing() (players []int) {
 	for i, p := range h.Players {
@@ -251,150 +259,250 @@ func (h *Hearts) currentlyPassing() (players []int) {
 	return
 }
 
-// passRight returns the target to the players right (toward end of array)
-func (h *Hearts) passRight(player int, cards []Card) int {
-	if player == PlayerFour {
-		return PlayerOne
+// playPhase contains the logic for playing a card during the play phase. Each trick goes
+// through the same steps: it is led, which sets the suit that has to be followed, the
+// other three players follow, and then it is resolved by the highest card of the led suit
+// taking it. Resolving the last trick of a round ends the round.
+func (h *Hearts) playPhase(p int, card Card) error {
+	if err := h.checkPlay(p, card); err != nil {
+		return err
+	}
+
+	if h.suit == "" {
+		h.lead(p, card)
 	} else {
-		return player + 1
+		h.follow(p, card)
 	}
-}
 
-// playPhase contains the logic for playing a card during the play phase.
-func (h *Hearts) playPhase(p int, cards ...Card) error {
-	if len(cards) != 1 {
-		return errors.New("player must play exactly one card")
+	// the trick is resolved once everyone has played into it
+	for _, player := range h.Players {
+		if player.Played == nil {
+			return nil
+		}
 	}
 
+	if len(h.Players[PlayerOne].Hand) == 0 {
+		h.nextRound()
+	} else {
+		h.nextTrick()
+	}
+
+	return nil
+}
+
+// lead plays the first card into a trick, which sets the suit that the others have to
+// follow.
+func (h *Hearts) lead(p int, card Card) {
+	h.suit = card.Suit()
+	h.place(p, card)
+	h.record(Event{Type: EventTrickLed, Trick: h.trick, Seat: p, Card: h.playedCard(card), Suit: h.suit})
+	h.breakHearts(p, card)
+}
+
+// follow plays a card into a trick that has already been led.
+func (h *Hearts) follow(p int, card Card) {
+	h.place(p, card)
+	h.breakHearts(p, card)
+}
+
+// place moves the card from the player's hand onto the table.
+func (h *Hearts) place(p int, card Card) {
 	hand := &h.Players[p].Hand
-	played := &h.Players[p].Played
-	keepPlaying := false
+	*hand = removeCard(*hand, card)
 
-	// check that the player has the card
-	if !hasCard(*hand, cards[0]) {
-		return fmt.Errorf("player %d does not have %d", p, cards)
-	}
+	h.Players[p].Played = h.playedCard(card)
+	h.lastPlayed = p
+	h.record(Event{Type: EventPlay, Trick: h.trick, Seat: p, Card: h.playedCard(card)})
+}
 
-	// if the player has the two of clubs, they MUST play it
-	if hasTwoOfClubs(*hand) {
-		if cards[0] != CardTwoOfClubs {
-			return fmt.Errorf(
-				"player has the two of clubs, but is trying to play %d",
-				cards[0],
-			)
-		}
+// breakHearts breaks hearts, if the card the player just played breaks them.
+func (h *Hearts) breakHearts(p int, card Card) {
+	if h.brokenHearted || !h.rules.breaksHearts(card) {
+		return
 	}
 
-	// if a suit was led, and the player MUST follow suit, UNLESS they don't have any
-	// cards in that suit
-	if h.suit != "" && h.suit != cards[0].Suit() {
-		if hasSuit(*hand, h.suit) {
-			return fmt.Errorf(
-				"must follow suit: %s, but player played %s",
-				h.suit,
-				cards[0].Suit(),
-			)
-		} else if h.trick == 1 && cards[0].Suit() == SuitHearts {
-			if !onlyHasHearts(*hand) {
-				return errors.New("cannot play a heart on the first trick")
-			}
-		}
-	} else if !h.brokenHearted && cards[0].Suit() == SuitHearts { // leading with a heart
-		if !onlyHasHearts(*hand) {
-			return errors.New("cannot lead with a heart until hearts are broken")
-		}
-	}
+	h.brokenHearted = true
+	h.record(Event{Type: EventHeartsBroken, Trick: h.trick, Seat: p, Card: h.playedCard(card)})
+}
 
-	*hand = removeCard(*hand, cards[0])
-	*played = &cards[0]
+// checkPlay returns a *RuleError if the rules don't allow the player to play the card
+// into the current trick.
+func (h *Hearts) checkPlay(p int, card Card) error {
+	switch code := h.brokenRule(p, card); code {
+	case "":
+		return nil
+
+	case CodeCardNotInHand:
+		return ruleError(
+			code,
+			p,
+			[]Card{card},
+			"player %d does not have the %s",
+			p+1, // players are described by id, the same way they are in a Perspective
+			card,
+		)
+
+	case CodeMustPlayTwoOfClubs:
+		return ruleError(
+			code,
+			p,
+			[]Card{card},
+			"player has the two of clubs, but is trying to play the %s",
+			card,
+		)
+
+	case CodeMustFollowSuit:
+		return ruleError(
+			code,
+			p,
+			[]Card{card},
+			"must follow suit: %s, but player played %s",
+			h.suit,
+			card.Suit(),
+		)
+
+	case CodeNoPointsOnFirstTrick:
+		return ruleError(code, p, []Card{card}, "cannot play the %s on the first trick", card)
+
+	default: // CodeHeartsNotBroken
+		return ruleError(code, p, []Card{card}, "cannot lead with a heart until hearts are broken")
+	}
+}
 
-	h.lastPlayed = p
+// brokenRule returns the code of the rule that doesn't allow the player to play the card
+// into the current trick, or an empty code if the card can be played. It holds every rule
+// about which cards can be played, so that playing a card and listing the cards that can
+// be played never disagree. It doesn't allocate, so that LegalMoves can be called at
+// every step of a simulated round.
+func (h *Hearts) brokenRule(p int, card Card) ErrorCode {
+	hand := h.Players[p].Hand
 
-	// look to see if any player have not yet played
-	for _, player := range h.Players {
-		if player.Played == nil {
-			keepPlaying = true // and if not set flag so we can continue
-		}
+	// check that the player has the card
+	if !hasCard(hand, card) {
+		return CodeCardNotInHand
 	}
 
-	// if no suit had been led before, then this card must be the new leading suit
-	if h.suit == "" {
-		h.suit = cards[0].Suit()
+	// if the player has the two of clubs, they MUST play it
+	if hasTwoOfClubs(hand) && card != CardTwoOfClubs {
+		return CodeMustPlayTwoOfClubs
 	}
 
-	// if no player was found who hasn't played, then the trick is over
-	if !keepPlaying {
-		if len(h.Players[PlayerOne].Hand) == 0 {
-			h.nextRound()
-		} else {
-			h.nextTrick()
+	// if a suit was led, and the player MUST follow suit, UNLESS they don't have any
+	// cards in that suit
+	if h.suit != "" && h.suit != card.Suit() {
+		if hasSuit(hand, h.suit) {
+			return CodeMustFollowSuit
+		} else if off := h.rules.offFirstTrick(); h.trick == 1 && off.Has(card) {
+			if NewCardSet(hand...)&^off != 0 { // the player has something else to play
+				return CodeNoPointsOnFirstTrick
+			}
+		}
+	} else if !h.brokenHearted && card.Suit() == SuitHearts { // leading with a heart
+		if !onlyHasHearts(hand) {
+			return CodeHeartsNotBroken
 		}
 	}
 
-	return nil
+	return ""
 }
 
 // currentlyPlaying returns either the player who has the two of clubs, or the last player
 // to take a trick
 func (h *Hearts) currentlyPlaying() (players []int) {
-	if h.lastPlayed != Nobody {
-		players = []int{nextPlayer(h.lastPlayed)}
+	if p := h.currentPlayer(); p != Nobody {
+		players = []int{p}
+	}
 
-	} else if h.lastTaken != Nobody {
-		players = []int{h.lastTaken}
+	return
+}
 
-	} else {
+// currentPlayer returns the index of the player whose turn it is to play a card. It is
+// the player after the last one to play, or the last player to take a trick, or, if no one
+// has taken a trick yet, the player with the two of clubs.
+func (h *Hearts) currentPlayer() int {
+	if h.lastPlayed != Nobody {
+		return nextPlayer(h.lastPlayed)
+	}
 
-		// if no one took the last trick, return the player with two of clubs
-		for i, p := range h.Players { // look through players...
-			for _, c := range p.Hand { // look through players' hands...
-				if c == Card(CardTwoOfClubs) {
-					players = []int{i}
-					return
-				}
-			}
+	if h.lastTaken != Nobody {
+		return h.lastTaken
+	}
+
+	for i, p := range h.Players {
+		if hasTwoOfClubs(p.Hand) {
+			return i
 		}
 	}
 
-	return
+	return Nobody
 }
 
 // nextRound advances to the next phase and increments the round number.
 func (h *Hearts) nextRound() {
 	h.nextTrick()
 	shot := Nobody
+	roundPoints := make([]int, 0, len(h.Players))
+	scores := make([]int, 0, len(h.Players))
+	sheet := RoundScore{Round: h.round, PassTo: roundToPassDirection(h.round)}
 
-	for i := 0; i < len(h.Players); i++ {
+	for i, player := range h.Players {
+		if player.roundScore == MoonPoints { // discovered that someone shot the moon
+			shot = i
+		}
+	}
+
+	for i := range h.Players {
 		player := &h.Players[i]
+		before := player.gameScore
 
-		if shot == Nobody { // everyone is taking their round score
-			if player.roundScore == 26 { // discovered that someone shot the moon
-				shot = i // mark that person as having shot
-				i = -1   // reset the loop with someone shooting; -1 so we don't skip 0
+		switch {
+		case shot == Nobody || h.rules.MoonShot == MoonShotNone:
+			player.gameScore -= player.roundScore
 
-			} else { // otherwise, assume no one shot and give everyone their round score
-				player.gameScore -= player.roundScore
-				player.roundScore = 0
+		case h.rules.MoonShot == MoonShotSubtractFromSelf:
+			if i == shot { // the shooter gets their points back, up to the target
+				player.gameScore += MoonPoints
+
+				if player.gameScore > h.rules.TargetScore {
+					player.gameScore = h.rules.TargetScore
+				}
 			}
 
-		} else if i != shot { // else another player shot the moon!
-			player.gameScore -= 26 // suck 26 points, loser!
+		default: // MoonShotAddToOthers
+			if i != shot { // another player shot the moon!
+				player.gameScore -= MoonPoints // suck 26 points, loser!
+			}
 		}
 
+		roundPoints = append(roundPoints, player.roundScore)
+		scores = append(scores, player.gameScore)
+		sheet.Points[i] = player.roundScore
+		sheet.Scored[i] = before - player.gameScore
+		sheet.Totals[i] = h.rules.TargetScore - player.gameScore
+		player.roundScore = 0
+
 		// detect player has crossed the threshhold and ended that game
 		if player.gameScore <= 0 {
 			h.finished = true
 		}
 	}
 
+	sheet.MoonShot = shot
+	h.scoreSheet = append(h.scoreSheet, sheet)
+	h.record(Event{Type: EventRoundScored, Seat: shot, RoundPoints: roundPoints, Scores: scores})
+
+	if h.finished {
+		h.record(Event{Type: EventGameFinished, Seat: Nobody, Winners: h.Winner()})
+	}
+
 	h.lastTaken = Nobody
 	h.phaseEnd = true
-	h.round++
 	h.NextPhase()
 }
 
-// nextTrick cleans up, adds up the points taken in the trick, figures out who takes them
-// and sets up for the next trick.
+// nextTrick resolves the trick: it cleans up, adds up the points taken in the trick,
+// figures out who takes them and sets up for the next trick, which has no led suit until
+// it is led.
 func (h *Hearts) nextTrick() {
 	var highestCard Card = Nobody
 	highestPlayer := Nobody
@@ -412,26 +520,83 @@ func (h *Hearts) nextTrick() {
 		}
 	}
 
+	trickTotal := sumTrickPoints(trick)
+	h.record(Event{Type: EventTrickTaken, Trick: h.trick, Seat: highestPlayer, Points: trickTotal})
+
+	taken := Trick{
+		Round:  h.round,
+		Number: h.trick,
+		Leader: h.trickLeader(),
+		Winner: highestPlayer,
+		Points: trickTotal,
+	}
+
+	for i, p := 0, taken.Leader; i < len(taken.Plays); i, p = i+1, nextPlayer(p) {
+		taken.Plays[i] = PlayMove{Seat: p, Card: trick[p]}
+		h.Players[highestPlayer].Taken = append(h.Players[highestPlayer].Taken, trick[p])
+	}
+
+	h.tricks = append(h.tricks, taken)
+
 	h.lastTaken = highestPlayer
 	h.lastTrick = trick
 	h.trick += 1
 	h.lastPlayed = Nobody
-	trickTotal := sumTrickPoints(trick)
+	h.suit = "" // the next trick hasn't been led yet
 	h.Players[highestPlayer].roundScore += trickTotal
+
+	// clear the table for the next trick
+	for i := range h.Players {
+		h.Players[i].Played = nil
+	}
+}
+
+// thisTrick returns the cards played into the current trick, in the order they were
+// played.
+func (h *Hearts) thisTrick() []Card {
+	trick := make([]Card, 0, 3)
+	p := h.trickLeader()
+
+	if p == Nobody {
+		return trick
+	}
+
+	for h.Players[p].Played != nil && len(trick) < 4 {
+		trick = append(trick, *h.Players[p].Played)
+		p = nextPlayer(p)
+	}
+
+	return trick
+}
+
+// trickLeader returns the index of the player who led the current trick, or Nobody if it
+// hasn't been led yet.
+func (h *Hearts) trickLeader() int {
+	if h.lastPlayed == Nobody {
+		return Nobody
+	}
+
+	// walk back from the last player to play to the first one
+	leader := h.lastPlayed
+
+	for {
+		previous := (leader + 1) % len(h.Players)
+
+		if previous == h.lastPlayed || h.Players[previous].Played == nil {
+			return leader
+		}
+
+		leader = previous
+	}
 }
 
 // check the hand for the given cards. Return true if the cards are in the hand, false if
 // they are not.
 func hasCard(hand []Card, cards ...Card) bool {
-	for _, card := range cards {
-		has := false
-		for _, heldCard := range hand {
-			if card.Compare(heldCard) == 0 {
-				has = true
-			}
-		}
+	held := NewCardSet(hand...)
 
-		if !has {
+	for _, card := range cards {
+		if !held.Has(card) {
 			return false
 		}
 	}
@@ -439,26 +604,27 @@ func hasCard(hand []Card, cards ...Card) bool {
 	return true
 }
 
-// hasSuit returns true if the given suit appears in the given hand
-func hasSuit(hand []Card, suit string) bool {
-	for _, c := range hand {
-		if c.Suit() == suit {
-			return true
+// missingCards returns the given cards that are not in the hand.
+func missingCards(hand []Card, cards []Card) []Card {
+	missing := []Card{}
+
+	for _, c := range cards {
+		if !hasCard(hand, c) {
+			missing = append(missing, c)
 		}
 	}
 
-	return false
+	return missing
+}
+
+// hasSuit returns true if the given suit appears in the given hand
+func hasSuit(hand []Card, suit string) bool {
+	return NewCardSet(hand...).Suit(suit) != 0
 }
 
 // hasTwoOfClubs returns true if the two of clubs is found in the given hand
 func hasTwoOfClubs(hand []Card) bool {
-	for _, c := range hand {
-		if c == CardTwoOfClubs {
-			return true
-		}
-	}
-
-	return false
+	return NewCardSet(hand...).Has(CardTwoOfClubs)
 }
 
 // nextPlayer returns the index to the "left" of a given player. Left, for our purposes,
@@ -474,32 +640,46 @@ func nextPlayer(lastPlayer int) int {
 	return player
 }
 
+// onlyHasHearts returns true if every card in the hand is a heart.
 func onlyHasHearts(hand []Card) bool {
-	for _, card := range hand {
-		if card.Suit() != SuitHearts {
-			return false
-		}
+	return NewCardSet(hand...)&^HeartsMask == 0
+}
+
+// playedCard returns a pointer to the card for a player's Played field or a logged event.
+// The pointers all point into the game's own table of cards, so that playing a card
+// doesn't allocate.
+func (h *Hearts) playedCard(c Card) *Card {
+	if h.cards == nil {
+		h.cards = newCardTable()
 	}
 
-	return true
+	return &h.cards[c]
 }
 
-// removeCard returns a new array without the given cards in it.
-func removeCard(hand []Card, cards ...Card) []Card {
-	new := make([]Card, 0, len(hand))
-	set := map[Card]bool{}
+// newCardTable returns a table holding one of each card, for a game's cards to point at.
+func newCardTable() *[52]Card {
+	cards := new([52]Card)
 
-	for _, c := range cards {
-		set[c] = true
+	for i := range cards {
+		cards[i] = Card(i)
 	}
 
+	return cards
+}
+
+// removeCard removes the given cards from the hand, keeping the rest in order. The hand's
+// array is reused, so anything else holding the hand sees the cards shift.
+func removeCard(hand []Card, cards ...Card) []Card {
+	removing := NewCardSet(cards...)
+	kept := hand[:0]
+
 	for _, c := range hand {
-		if !set[c] {
-			new = append(new, c)
+		if !removing.Has(c) {
+			kept = append(kept, c)
 		}
 	}
 
-	return new
+	return kept
 }
 
 // sort sorts a hand using quicksort.
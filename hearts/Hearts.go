@@ -1,9 +1,12 @@
 package hearts
 
+import "math/rand"
+
 const pointLimit = 100
 
-// Hearts is the underlying data of the game. It should be storable in the database with
-// few, if any, modifications.
+// Hearts is the underlying data of the game. It can be stored with MarshalJSON or
+// MarshalBinary, which keep the complete state of the game, and restored with the
+// matching unmarshal method.
 type Hearts struct {
 
 	// These are the four players playing the game. Exactly four players are required in
@@ -11,7 +14,7 @@ type Hearts struct {
 	Players [4]Player
 
 	// brokenHearted is set to true if hearts have been sloughed. The Jamoke does not
-	// count as a heart.
+	// count as a heart, unless the rules say that it breaks hearts.
 	brokenHearted bool
 
 	// finished keeps track of whether the game has ended or not
@@ -33,15 +36,40 @@ type Hearts struct {
 	// phaseEnd is a flag that signals that the current phase has ended.
 	phaseEnd bool
 
+	// rules are the house rules the game is played with.
+	rules Rules
+
 	// round is the round number that is currently being played. round starts with 1.
 	round int
 
+	// seed is the seed that each round's deal is generated from.
+	seed int64
+
+	// shuffle shuffles the deck before each deal. If it is nil, a SeededShuffler is used.
+	shuffle Shuffler
+
 	// trick is the trick number that is currently being played. trick start with 1.
 	trick int
 
 	// suit is the suit of the first card played into the trick. It is the suit that must
 	// be followed
 	suit string
+
+	// events is the log of everything that has happened in the game.
+	events []Event
+
+	// tricks are the tricks that have been taken so far this round, and lastRound are the
+	// tricks of the round before.
+	tricks    []Trick
+	lastRound []Trick
+
+	// scoreSheet is how every round that has been played to the end was scored.
+	scoreSheet []RoundScore
+
+	// cards holds one of each card for Played and the cards of logged events to point at,
+	// so that playing a card doesn't allocate. Each game has its own, which Clone copies,
+	// so nothing changed through one of its pointers reaches any other game.
+	cards *[52]Card
 }
 
 // Player represents a players hand, the tricks they've taken, and the card that was
@@ -58,15 +86,17 @@ type Player struct {
 	// Hand and setting Played to that card.
 	Hand []Card
 
-	// Taken represents the tricks that the player has taken. Each trick consists of four
-	// cards which have been played by each player each round. At the end of the game,
-	// cards which have a point value will be totaled and added to each players score.
+	// Taken represents the tricks that the player has taken this round. Each trick
+	// consists of four cards which have been played by each player, and they are kept
+	// four at a time in the order they were played. At the end of the round, cards which
+	// have a point value will be totaled and added to each players score.
 	Taken []Card
 
 	// Played is the card that a player has chosen to play for the round. In a physical
 	// game, this card would be played into the middle of the table. It is kept with the
 	// player in this struct so that, at the end of the round, each card can be easily
-	// connected with the player that played it.
+	// connected with the player that played it. The card it points to is shared with the
+	// game's log, so it should never be changed through the pointer.
 	Played *Card
 
 	// Receiving represents the cards that are being passed to this player during the
@@ -84,16 +114,47 @@ type Player struct {
 	roundScore int
 }
 
+// Options changes the way a new game is created. The zero value is a game with a seed
+// drawn from crypto/rand.
+type Options struct {
+
+	// Seed is the seed that every deal in the game is generated from. Two games with the
+	// same Seed are dealt the same hands each round. If Seed is 0, a seed is drawn from
+	// Source instead.
+	Seed int64
+
+	// Source is used to pick the game's seed when Seed is 0. If Source is nil as well,
+	// the seed is drawn from crypto/rand.
+	Source rand.Source
+
+	// Shuffler shuffles the deck before each deal. If it is nil, a SeededShuffler is
+	// used. Only the Shufflers in this package are remembered when a game is stored;
+	// games with any other Shuffler are restored with a SeededShuffler.
+	Shuffler Shuffler
+
+	// Rules are the house rules the game is played with. Anything left unset in them
+	// follows the standard rules.
+	Rules Rules
+}
+
 // New creates a new game of Hearts. It should represent a whole game, not just a round.
-func New() Hearts {
+// Only the first Options given, if any, are used.
+func New(options ...Options) Hearts {
 	players := [4]Player{}
 	hearts := Hearts{}
+	opts := Options{}
+
+	if len(options) > 0 {
+		opts = options[0]
+	}
+
+	rules := opts.Rules.withDefaults()
 
 	for i := 0; i < 4; i++ {
 		players[i] = Player{
 			Hand:      make([]Card, 0, 13),
-			Taken:     make([]Card, 0, 13),
-			gameScore: pointLimit,
+			Taken:     make([]Card, 0, 52),
+			gameScore: rules.TargetScore,
 		}
 	}
 
@@ -102,6 +163,14 @@ func New() Hearts {
 	hearts.trick = 1
 	hearts.lastPlayed = -1
 	hearts.lastTaken = -1
+	hearts.seed = opts.Seed
+	hearts.shuffle = opts.Shuffler
+	hearts.rules = rules
+	hearts.tricks = make([]Trick, 0, tricksPerRound)
+
+	if hearts.seed == 0 {
+		hearts.seed = newSeed(opts.Source)
+	}
 
 	return hearts
 }
@@ -167,6 +167,9 @@ func TestHeartsPassTurns(t *testing.T) {
 	twoOfClubs := findTwoOfClubs(game)
 	checkActivePlayers(t, game, []int{twoOfClubs})
 
+	// no card should be in two places after passing
+	checkNoDuplicates(t, game)
+
 	checkCardsReceived(t, game.Players[0].Hand, secondPlayerPassed)
 	checkCardsReceived(t, game.Players[1].Hand, thirdPlayerPassed)
 	checkCardsReceived(t, game.Players[2].Hand, fourthPlayer.Taken)
@@ -487,6 +490,50 @@ func TestNoLeadingHeartsBeforeBroken(t *testing.T) {
 	play(t, &h, PlayerOne, false, card(h.Players[PlayerOne].Hand, SuitHearts))
 }
 
+func TestLegalMoves(t *testing.T) {
+	h := setupCannedHands(handFull)
+
+	// anything can be passed
+	checkLegalMoves(t, &h, PlayerOne, h.Players[PlayerOne].Hand...)
+
+	h.phase = PhasePlay
+
+	// only the Two of Clubs can lead the first trick, and only its holder can lead it
+	checkLegalMoves(t, &h, PlayerOne)
+	checkLegalMoves(t, &h, PlayerTwo, CardTwoOfClubs)
+
+	play(t, &h, PlayerTwo, false, CardTwoOfClubs)
+
+	// player one must follow with a club
+	checkLegalMoves(t, &h, PlayerOne, 16, 20, 24)
+
+	h = setupCannedHands(handSmall)
+	h.phase = PhasePlay
+	h.lastTaken = PlayerFour
+
+	// player four can lead anything but a heart
+	checkLegalMoves(t, &h, PlayerFour, 3, 11, 19, 39, 43, 47, 51)
+
+	play(t, &h, PlayerFour, false, 19)
+	play(t, &h, PlayerThree, false, 18)
+
+	// player two is out of clubs, but can't drop a heart on the first trick
+	checkLegalMoves(t, &h, PlayerTwo, 1, 5, 9, 41, 45, 49)
+
+	// every card that LegalMoves returns can be played
+	for _, c := range h.LegalMoves(PlayerTwo) {
+		clone := setupCannedHands(handSmall)
+		clone.phase = PhasePlay
+		clone.lastTaken = PlayerFour
+		play(t, &clone, PlayerFour, false, 19)
+		play(t, &clone, PlayerThree, false, 18)
+		play(t, &clone, PlayerTwo, false, c)
+	}
+
+	h.finished = true
+	checkLegalMoves(t, &h, PlayerTwo)
+}
+
 func TestRoundEnd(t *testing.T) {
 	h := setupCannedHands(handFinal)
 	h.phase = PhasePlay
@@ -521,6 +568,10 @@ func TestRoundEnd(t *testing.T) {
 		t.Error("the round should have ended, but we are still in the pass phase")
 	}
 
+	if h.trick != 1 || h.brokenHearted {
+		t.Errorf("expected the new round to start at trick 1 with hearts unbroken, but it is at trick %d and broken is %t", h.trick, h.brokenHearted)
+	}
+
 	finalScore := h.Score()
 
 	for _, player := range h.Players {
@@ -542,6 +593,46 @@ func TestRoundEnd(t *testing.T) {
 	}
 }
 
+func TestNextTrickClearsTable(t *testing.T) {
+	h := setupCannedHands(handFull)
+	h.phase = PhasePlay
+
+	play(t, &h, PlayerTwo, false, CardTwoOfClubs)
+	play(t, &h, PlayerOne, false, 24)
+	play(t, &h, PlayerFour, false, 15)
+	play(t, &h, PlayerThree, false, 22)
+
+	if h.suit != "" {
+		t.Errorf("expected no suit to be led in the next trick, but %s is", h.suit)
+	}
+
+	for p, player := range h.Players {
+		if player.Played != nil {
+			t.Errorf("expected player %d to have nothing on the table, but they have the %s", p+1, *player.Played)
+		}
+	}
+
+	// player 1 took the trick with the King of Clubs and can lead any suit
+	play(t, &h, PlayerOne, false, 0)
+}
+
+func TestPlayedNotShared(t *testing.T) {
+	h := setupCannedHands(handFull)
+	h.phase = PhasePlay
+	other := setupCannedHands(handFull)
+	other.phase = PhasePlay
+
+	play(t, &h, PlayerTwo, false, CardTwoOfClubs)
+	play(t, &other, PlayerTwo, false, CardTwoOfClubs)
+
+	// changing the card through one game's pointer doesn't reach any other game
+	*h.Players[PlayerTwo].Played = CardJamoke
+
+	if played := other.Players[PlayerTwo].Played; played == nil || *played != CardTwoOfClubs {
+		t.Errorf("expected the other game to still have the Two of Clubs on the table, but it has %v", played)
+	}
+}
+
 func TestMoonShot(t *testing.T) {
 	h := setupCannedHands(handFinal)
 	h.phase = PhasePlay
@@ -578,6 +669,10 @@ func TestMoonShot(t *testing.T) {
 		t.Error("the round should have ended, but we are still in the pass phase")
 	}
 
+	if h.trick != 1 || h.brokenHearted {
+		t.Errorf("expected the new round to start at trick 1 with hearts unbroken, but it is at trick %d and broken is %t", h.trick, h.brokenHearted)
+	}
+
 	finalScore := h.Score()
 
 	for p, start := range startingScore {
@@ -700,6 +795,135 @@ func checkActivePlayers(
 	}
 }
 
+func checkLegalMoves(t *testing.T, game *Hearts, player int, expected ...Card) {
+	t.Helper()
+	legal := game.LegalMoves(player)
+
+	if len(legal) != len(expected) || !hasCards(legal, expected...) {
+		t.Errorf("expected player %d to be able to play %v but received %v", player, expected, legal)
+	}
+}
+
+func TestPassFromHand(t *testing.T) {
+	h := New(Options{Seed: 4})
+	h.Setup()
+	passing := append([]Card{}, h.Players[PlayerTwo].Hand[:3]...)
+
+	// the cards share an array with the hand they are passed from
+	if err := h.Play(PlayerTwo, h.Players[PlayerTwo].Hand[:3]...); err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	checkCardsReceived(t, h.Players[PlayerOne].Receiving, passing)
+}
+
+func TestPlayAllocations(t *testing.T) {
+	start, plays := scriptedPlays(t)
+	games := []Hearts{start.Clone(), start.Clone()}
+	run := 0
+
+	// AllocsPerRun plays once to warm up, so each run gets its own game
+	allocs := testing.AllocsPerRun(1, func() {
+		game := &games[run]
+		run++
+
+		for _, m := range plays {
+			if err := game.Play(m.Seat, m.Card); err != nil {
+				t.Fatalf("expected no error but received: %s", err)
+			}
+		}
+	})
+
+	if allocs != 0 {
+		t.Errorf("expected playing tricks not to allocate, but it allocated %v times", allocs)
+	}
+}
+
+func TestLegalMovesAllocations(t *testing.T) {
+	game, plays := scriptedPlays(t)
+
+	// partway through a trick, partway through the round, most cards can't be played
+	for _, m := range plays[:18] {
+		if err := game.Apply(m); err != nil {
+			t.Fatalf("expected no error but received: %s", err)
+		}
+	}
+
+	p := game.PlayersTurn()[0]
+
+	if legal := game.LegalMoves(p); len(legal) == len(game.Players[p].Hand) {
+		t.Fatalf("expected player %d to have cards they can't play, but they can play %v", p+1, legal)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() { game.LegalMoves(p) })
+
+	if allocs != 1 {
+		t.Errorf("expected LegalMoves to allocate only the slice it returns, but it allocated %v times", allocs)
+	}
+}
+
+func BenchmarkPlay(b *testing.B) {
+	start, plays := scriptedPlays(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		game := start.Clone()
+		b.StartTimer()
+
+		for _, m := range plays {
+			game.Play(m.Seat, m.Card)
+		}
+	}
+}
+
+// scriptedPlays returns a game at the start of a round's play phase, and the plays that
+// take it through every trick but the last. The last trick is left out because ending the
+// round deals new hands.
+func scriptedPlays(t testing.TB) (Hearts, []PlayMove) {
+	t.Helper()
+	dealer := New(Options{Seed: 1})
+	hands := dealer.Deal(4)
+
+	start, err := FromPosition(Position{Hands: hands, Leader: Nobody, TrickNumber: 1, Round: 4})
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	game := start.Clone()
+	plays := []PlayMove{}
+
+	for len(plays) < 48 {
+		p := game.PlayersTurn()[0]
+		m := PlayMove{Seat: p, Card: game.LegalMoves(p)[0]}
+
+		if err := game.Apply(m); err != nil {
+			t.Fatalf("expected no error but received: %s", err)
+		}
+
+		plays = append(plays, m)
+	}
+
+	return start, plays
+}
+
+func checkNoDuplicates(t *testing.T, game *Hearts) {
+	t.Helper()
+	seen := map[Card]bool{}
+
+	for _, p := range game.Players {
+		for _, c := range p.Hand {
+			if seen[c] {
+				t.Errorf("the %s is in play more than once", c)
+			}
+
+			seen[c] = true
+		}
+	}
+}
+
 func checkCardsReceived(t *testing.T, hand []Card, cards []Card) {
 	handMap := make(map[Card]bool)
 
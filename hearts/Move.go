@@ -0,0 +1,138 @@
+package hearts
+
+import (
+	"reflect"
+
+	"github.com/nolwn/go-hearts/game"
+)
+
+// PassMove picks the cards a player passes during the pass phase. Exactly three cards
+// must be passed.
+type PassMove struct {
+
+	// Seat is the index of the player passing the cards.
+	Seat int `json:"seat"`
+
+	// Cards are the cards being passed.
+	Cards []Card `json:"cards"`
+}
+
+// Player returns the index of the player passing the cards.
+func (m PassMove) Player() int {
+	return m.Seat
+}
+
+// PlayMove plays a card into the trick during the play phase.
+type PlayMove struct {
+
+	// Seat is the index of the player playing the card.
+	Seat int `json:"seat"`
+
+	// Card is the card being played.
+	Card Card `json:"card"`
+}
+
+// Player returns the index of the player playing the card.
+func (m PlayMove) Player() int {
+	return m.Seat
+}
+
+// Apply makes a move in the game. Only PassMoves can be made during the pass phase, and
+// only PlayMoves during the play phase, either of which can be given by pointer. Any other
+// kind of move is not part of Hearts.
+//
+// A *RuleError will be returned if it isn't the player's turn, if the move doesn't belong
+// in the current phase, or if the rules don't allow it.
+func (h *Hearts) Apply(move game.Move) error {
+	switch m := move.(type) {
+	case PassMove:
+		return h.pass(m)
+
+	case PlayMove:
+		return h.play(m)
+
+	case *PassMove:
+		if m != nil {
+			return h.pass(*m)
+		}
+
+	case *PlayMove:
+		if m != nil {
+			return h.play(*m)
+		}
+	}
+
+	if isNil(move) {
+		return ruleError(CodeUnknownMove, Nobody, nil, "no move was given")
+	}
+
+	if err := h.checkTurn(move.Player()); err != nil {
+		return err
+	}
+
+	return ruleError(CodeUnknownMove, move.Player(), nil, "%T is not a move in Hearts", move)
+}
+
+// isNil returns true if there is no move, including a nil pointer of any type of move,
+// whose Player can't be called.
+func isNil(move game.Move) bool {
+	if move == nil {
+		return true
+	}
+
+	switch v := reflect.ValueOf(move); v.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Map, reflect.Ptr, reflect.Slice:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
+
+// checkTurn returns a *RuleError if the game is finished or if it isn't the player's turn.
+func (h *Hearts) checkTurn(player int) error {
+	if h.finished {
+		return ruleError(CodeGameFinished, player, nil, "the game is finished")
+	}
+
+	if !h.isTurn(player) {
+		return ruleError(CodeNotYourTurn, player, nil, "it is not player %d's turn", player+1)
+	}
+
+	return nil
+}
+
+// pass applies a PassMove.
+func (h *Hearts) pass(m PassMove) error {
+	if err := h.checkTurn(m.Seat); err != nil {
+		return err
+	}
+
+	if h.phase != PhasePass {
+		return ruleError(
+			CodeWrongPhase,
+			m.Seat,
+			m.Cards,
+			"cards can only be passed during the pass phase",
+		)
+	}
+
+	return h.passPhase(m.Seat, m.Cards...)
+}
+
+// play applies a PlayMove.
+func (h *Hearts) play(m PlayMove) error {
+	if err := h.checkTurn(m.Seat); err != nil {
+		return err
+	}
+
+	if h.phase != PhasePlay {
+		return ruleError(
+			CodeWrongPhase,
+			m.Seat,
+			[]Card{m.Card},
+			"cards can only be played during the play phase",
+		)
+	}
+
+	return h.playPhase(m.Seat, m.Card)
+}
@@ -0,0 +1,59 @@
+package hearts
+
+import (
+	"errors"
+	"testing"
+)
+
+type claimMove struct{ seat int }
+
+func (m claimMove) Player() int { return m.seat }
+
+func TestApplyPhase(t *testing.T) {
+	h := setupCannedHands(handFull)
+	hand := h.Players[PlayerTwo].Hand
+
+	// playing a card during the pass phase isn't allowed
+	if err := h.Apply(PlayMove{Seat: PlayerTwo, Card: CardTwoOfClubs}); err == nil {
+		t.Error("expected an error for a PlayMove in the pass phase")
+	}
+
+	if err := h.Apply(PassMove{Seat: PlayerTwo, Cards: hand[:3]}); err != nil {
+		t.Errorf("expected no error but received: %s", err)
+	}
+
+	h.phase = PhasePlay
+
+	// passing cards during the play phase isn't allowed either
+	if err := h.Apply(PassMove{Seat: PlayerTwo, Cards: hand[3:6]}); err == nil {
+		t.Error("expected an error for a PassMove in the play phase")
+	}
+
+	if err := h.Apply(claimMove{seat: PlayerTwo}); err == nil {
+		t.Error("expected an error for a move that isn't part of Hearts")
+	}
+
+	var re *RuleError
+
+	if err := h.Apply(nil); !errors.As(err, &re) || re.Code != CodeUnknownMove {
+		t.Errorf("expected a %s error for a missing move, but received %v", CodeUnknownMove, err)
+	}
+
+	// nil pointers are missing moves too, whatever kind of move they point to
+	for _, move := range []interface{ Player() int }{(*PlayMove)(nil), (*PassMove)(nil), (*claimMove)(nil)} {
+		if err := h.Apply(move); !errors.As(err, &re) || re.Code != CodeUnknownMove {
+			t.Errorf("expected a %s error for a nil %T, but received %v", CodeUnknownMove, move, err)
+		}
+	}
+
+	if err := h.Apply(PlayMove{Seat: PlayerOne, Card: h.Players[PlayerOne].Hand[0]}); err == nil {
+		t.Error("expected an error for a move by a player whose turn it isn't")
+	}
+
+	// moves can be given by pointer
+	if err := h.Apply(&PlayMove{Seat: PlayerTwo, Card: CardTwoOfClubs}); err != nil {
+		t.Errorf("expected no error but received: %s", err)
+	}
+
+	checkActivePlayers(t, &h, []int{PlayerOne})
+}
@@ -43,8 +43,15 @@ func (h *Hearts) NextPhase() (int error) {
 	}
 
 	if h.phase == PhasePlay {
-		h.deal()
 		h.round++ // each passing phase signifies the start of a new round
+		h.trick = 1
+		h.brokenHearted = false
+		h.clearTaken()
+		h.deal()
+
+		for i := range h.Players {
+			h.Players[i].hasPassed = false
+		}
 
 		// every fourth round skips the passing phase
 		if !(h.round%4 == 0) {
@@ -58,6 +65,7 @@ func (h *Hearts) NextPhase() (int error) {
 			h.Players[i].Receiving = []Card{}
 		}
 
+		h.record(Event{Type: EventPassesResolved, Seat: Nobody})
 		h.phase++
 	}
 
@@ -86,8 +94,10 @@ func mergeCards(hand []Card, cards []Card) []Card {
 	for i := 0; i < capacity; i++ {
 		if h >= len(hand) { // all hand cards are already added...
 			newHand = append(newHand, cards[c])
+			c++
 		} else if c >= len(cards) { // ...all cards are already added..
 			newHand = append(newHand, hand[h])
+			h++
 		} else if hand[h] < cards[c] { // ... the next hand card is smaller...
 			newHand = append(newHand, hand[h])
 			h++
@@ -0,0 +1,161 @@
+package hearts
+
+import "fmt"
+
+// Position describes a moment in the play phase of a round: who holds what, what has been
+// played into the current trick, and what has happened so far in the round. A game can be
+// created from a Position to play out the rest of the round, which is what search-based
+// bots and "what if" analysis need.
+type Position struct {
+
+	// Hands are the cards each player is holding.
+	Hands [4][]Card
+
+	// Trick are the cards played into the current trick, in the order they were played.
+	Trick []Card
+
+	// Leader is the index of the player who led, or is about to lead, the current trick.
+	// On the first trick, Leader can be Nobody, in which case the holder of the Two of
+	// Clubs leads.
+	Leader int
+
+	// TrickNumber is the number of the current trick. It starts with 1.
+	TrickNumber int
+
+	// Broken is true if hearts have been broken.
+	Broken bool
+
+	// Points are the points each player has taken so far in the round.
+	Points [4]int
+
+	// PointsToLose are each player's distance from losing at the start of the round, as
+	// a Perspective gives them. If it is empty, every player starts at the target score.
+	PointsToLose []int
+
+	// Round is the round number.
+	Round int
+
+	// Rules are the house rules that the round is played with.
+	Rules Rules
+}
+
+// FromPosition creates a game in the play phase from a Position. Every player's score
+// starts at their PointsToLose, or at the target score if the Position doesn't have them,
+// so that the round is scored the way it would be in the real game. An error is returned
+// if the Position doesn't describe a real moment in a round.
+func FromPosition(pos Position) (Hearts, error) {
+	h := New(Options{Rules: pos.Rules, Seed: 1}) // the seed only matters once the round ends
+	seen := map[Card]bool{}
+
+	if pos.Round < 1 || pos.TrickNumber < 1 {
+		return h, fmt.Errorf("round %d, trick %d is not a real point in a game", pos.Round, pos.TrickNumber)
+	}
+
+	if pos.Leader < Nobody || pos.Leader > PlayerFour {
+		return h, fmt.Errorf("%d can't lead the trick", pos.Leader)
+	}
+
+	// a trick that everyone has played into has already been taken
+	if len(pos.Trick) > 3 {
+		return h, fmt.Errorf("trick %d has %d cards, but a trick in play has at most 3", pos.TrickNumber, len(pos.Trick))
+	}
+
+	// without a leader, the trick must be the first one and someone must hold the Two of
+	// Clubs to lead it
+	if pos.Leader == Nobody && (len(pos.Trick) > 0 || pos.TrickNumber != 1 || !holdsTwoOfClubs(pos.Hands)) {
+		return h, fmt.Errorf("trick %d needs a leader", pos.TrickNumber)
+	}
+
+	if len(pos.PointsToLose) > 0 && len(pos.PointsToLose) != len(h.Players) {
+		return h, fmt.Errorf("there are %d scores for %d players", len(pos.PointsToLose), len(h.Players))
+	}
+
+	for p, score := range pos.PointsToLose {
+		if score <= 0 {
+			return h, fmt.Errorf("player %d has already lost", p+1)
+		}
+
+		h.Players[p].gameScore = score
+	}
+
+	for p := range h.Players {
+		hand := make([]Card, len(pos.Hands[p]))
+		copy(hand, pos.Hands[p])
+		sort(hand, 0, len(hand)-1)
+
+		h.Players[p].Hand = hand
+		h.Players[p].roundScore = pos.Points[p]
+	}
+
+	p := pos.Leader
+
+	for _, c := range pos.Trick {
+		h.Players[p].Played = h.playedCard(c)
+		h.lastPlayed = p
+		p = nextPlayer(p)
+	}
+
+	for _, c := range append(h.allHands(), pos.Trick...) {
+		if c < 0 || c > 51 || seen[c] {
+			return h, fmt.Errorf("the %d card can only be in play once", c)
+		}
+
+		seen[c] = true
+	}
+
+	// everyone who has played into the trick holds one card fewer than the rest
+	held := [4]int{}
+
+	for i, player := range h.Players {
+		held[i] = len(player.Hand)
+
+		if player.Played != nil {
+			held[i]++
+		}
+
+		if held[i] == 0 || held[i] != held[PlayerOne] {
+			return h, fmt.Errorf("player %d is holding the wrong number of cards", i+1)
+		}
+	}
+
+	if len(pos.Trick) > 0 {
+		h.suit = pos.Trick[0].Suit()
+	}
+
+	h.reserveEvents()
+	h.phase = PhasePlay
+	h.lastTaken = pos.Leader
+	h.trick = pos.TrickNumber
+	h.brokenHearted = pos.Broken
+	h.round = pos.Round
+
+	for i := range h.Players {
+		h.Players[i].hasPassed = true
+	}
+
+	return h, nil
+}
+
+// holdsTwoOfClubs returns true if any of the hands holds the Two of Clubs.
+func holdsTwoOfClubs(hands [4][]Card) bool {
+	for _, hand := range hands {
+		for _, c := range hand {
+			if c == CardTwoOfClubs {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// allHands returns every card held by any player.
+func (h *Hearts) allHands() []Card {
+	cards := make([]Card, 0, 52)
+
+	for _, p := range h.Players {
+		cards = append(cards, p.Hand...)
+	}
+
+	return cards
+}
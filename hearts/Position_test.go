@@ -0,0 +1,149 @@
+package hearts
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFromPosition(t *testing.T) {
+	pos := Position{
+		Hands: [4][]Card{
+			{4, 8, 12, 28, 32, 36, 40, 44, 48},
+			{1, 5, 9, 29, 33, 37, 41, 45, 49},
+			{2, 6, 10, 22, 30, 34, 42, 50},
+			{3, 11, 27, 35, 39, 43, 47, 51},
+		},
+		Trick:       []Card{19, 18},
+		Leader:      PlayerFour,
+		TrickNumber: 5,
+		Points:      [4]int{0, 3, 0, 1},
+		Round:       2,
+	}
+
+	h, err := FromPosition(pos)
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	checkActivePlayers(t, &h, []int{PlayerTwo})
+
+	if h.suit != SuitClubs || h.trick != 5 || h.Round() != 2 || h.Phase() != PhasePlay {
+		t.Errorf("expected trick 5 of round 2, led with clubs, but received %+v", h)
+	}
+
+	if !compareCards(h.thisTrick(), pos.Trick) || h.trickLeader() != PlayerFour {
+		t.Errorf("expected player four to have led %v but received %v", pos.Trick, h.thisTrick())
+	}
+
+	// player two is out of clubs, and the trick gets finished off
+	play(t, &h, PlayerTwo, false, 29)
+	play(t, &h, PlayerOne, false, 28)
+
+	if h.lastTaken != PlayerFour || h.Players[PlayerFour].roundScore != 3 {
+		t.Errorf("expected player four to take the trick and have 3 points")
+	}
+
+	// changing the game doesn't change the position
+	if len(pos.Hands[PlayerTwo]) != 9 {
+		t.Error("expected the position's hands not to change")
+	}
+}
+
+func TestFromPositionPointsToLose(t *testing.T) {
+	// player one leads the last trick, with every other point already taken
+	pos := Position{
+		Hands:        [4][]Card{{38}, {27}, {0}, {13}},
+		Leader:       PlayerOne,
+		TrickNumber:  13,
+		Broken:       true,
+		Points:       [4]int{24, 0, 0, 0},
+		PointsToLose: []int{40, 60, 70, 80},
+		Round:        2,
+		Rules:        Rules{MoonShot: MoonShotSubtractFromSelf},
+	}
+
+	h, err := FromPosition(pos)
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	play(t, &h, PlayerOne, false, 38)
+	play(t, &h, PlayerFour, false, 13)
+	play(t, &h, PlayerThree, false, 0)
+	play(t, &h, PlayerTwo, false, 27)
+
+	// shooting the moon gives player one back the points they had lost
+	expected := map[int]int{PlayerOne: 66, PlayerTwo: 60, PlayerThree: 70, PlayerFour: 80}
+
+	if score := h.Score(); !reflect.DeepEqual(score, expected) {
+		t.Errorf("expected the scores %v but received %v", expected, score)
+	}
+
+	pos.PointsToLose = []int{40, 60, 70}
+
+	if _, err := FromPosition(pos); err == nil {
+		t.Error("expected an error for a position without a score for every player")
+	}
+
+	pos.PointsToLose = []int{40, 60, 0, 80}
+
+	if _, err := FromPosition(pos); err == nil {
+		t.Error("expected an error for a position in which a player has already lost")
+	}
+}
+
+func TestFromPositionInvalid(t *testing.T) {
+	positions := map[string]Position{
+		"duplicate card": {
+			Hands:       [4][]Card{{0}, {0}, {2}, {3}},
+			Leader:      PlayerOne,
+			TrickNumber: 13,
+			Round:       1,
+		},
+		"uneven hands": {
+			Hands:       [4][]Card{{0, 4}, {1}, {2}, {3}},
+			Leader:      PlayerOne,
+			TrickNumber: 12,
+			Round:       1,
+		},
+		"full trick": {
+			Hands:       [4][]Card{{0}, {1}, {2}, {3}},
+			Trick:       []Card{4, 5, 6, 7},
+			Leader:      PlayerOne,
+			TrickNumber: 12,
+			Round:       1,
+		},
+		"trick without a leader": {
+			Hands:       [4][]Card{{}, {1}, {2}, {3}},
+			Trick:       []Card{0},
+			Leader:      Nobody,
+			TrickNumber: 13,
+			Round:       1,
+		},
+		"later trick without a leader": {
+			Hands:       [4][]Card{{0}, {1}, {2}, {3}},
+			Leader:      Nobody,
+			TrickNumber: 13,
+			Round:       1,
+		},
+		"first trick without the Two of Clubs": {
+			Hands:       [4][]Card{{0}, {1}, {2}, {3}},
+			Leader:      Nobody,
+			TrickNumber: 1,
+			Round:       1,
+		},
+		"no round": {
+			Hands:       [4][]Card{{0}, {1}, {2}, {3}},
+			Leader:      PlayerOne,
+			TrickNumber: 13,
+		},
+	}
+
+	for name, pos := range positions {
+		if _, err := FromPosition(pos); err == nil {
+			t.Errorf("%s: expected an error but did not receive one", name)
+		}
+	}
+}
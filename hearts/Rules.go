@@ -0,0 +1,103 @@
+package hearts
+
+// MoonPoints is the number of points in a round. Taking all of them is shooting the moon.
+const MoonPoints = 26
+
+// MoonShot names a way of scoring a round in which one player took every point.
+type MoonShot string
+
+const (
+
+	// MoonShotAddToOthers gives every other player 26 points and the shooter none. This
+	// is the standard rule.
+	MoonShotAddToOthers MoonShot = "addToOthers"
+
+	// MoonShotSubtractFromSelf takes 26 points away from the shooter's score, down to no
+	// lower than 0, and gives the other players none.
+	MoonShotSubtractFromSelf MoonShot = "subtractFromSelf"
+
+	// MoonShotNone scores a moon shot like any other round: the shooter takes 26 points.
+	MoonShotNone MoonShot = "none"
+)
+
+// Rules are the house rules that a game is played with. The zero value is the standard
+// game: play to 100, no hearts on the first trick but the Queen of Spades allowed on it,
+// the Queen of Spades doesn't break hearts, and shooting the moon gives everyone else 26
+// points.
+type Rules struct {
+
+	// TargetScore is the score that ends the game when a player reaches it. If it is 0,
+	// the game is played to 100.
+	TargetScore int `json:"targetScore"`
+
+	// HeartsOnFirstTrick allows hearts to be played on the first trick of a round.
+	HeartsOnFirstTrick bool `json:"heartsOnFirstTrick"`
+
+	// QueenOffFirstTrick keeps the Queen of Spades off the first trick of a round, the same
+	// way hearts are kept off it.
+	QueenOffFirstTrick bool `json:"queenOffFirstTrick"`
+
+	// QueenBreaksHearts makes playing the Queen of Spades break hearts, the same way
+	// playing a heart does.
+	QueenBreaksHearts bool `json:"queenBreaksHearts"`
+
+	// MoonShot is how a round is scored when one player takes every point. If it is
+	// empty, MoonShotAddToOthers is used.
+	MoonShot MoonShot `json:"moonShot"`
+
+	// Takebacks allows moves to be taken back with Undo. It is meant for casual and
+	// teaching tables.
+	Takebacks bool `json:"takebacks"`
+
+	// KibitzDelay lets kibitzers, who watch without playing, see every hand. While it is
+	// set, everyone watching the game sees it as it was this many tricks ago, so that the
+	// hands kibitzers see are never up to date. If it is 0, spectators watch live and
+	// never see anyone's hand.
+	KibitzDelay int `json:"kibitzDelay,omitempty"`
+}
+
+// DefaultRules returns the rules of a standard game, with every default filled in.
+func DefaultRules() Rules {
+	return Rules{}.withDefaults()
+}
+
+// Rules returns the rules that the game is being played with.
+func (h *Hearts) Rules() Rules {
+	return h.rules
+}
+
+// withDefaults returns the rules with the defaults filled in for anything left unset.
+func (r Rules) withDefaults() Rules {
+	if r.TargetScore <= 0 {
+		r.TargetScore = pointLimit
+	}
+
+	switch r.MoonShot {
+	case MoonShotSubtractFromSelf, MoonShotNone:
+	default:
+		r.MoonShot = MoonShotAddToOthers
+	}
+
+	return r
+}
+
+// breaksHearts returns true if playing the card breaks hearts.
+func (r Rules) breaksHearts(card Card) bool {
+	return card.Suit() == SuitHearts || (r.QueenBreaksHearts && card == CardJamoke)
+}
+
+// offFirstTrick returns the cards that can't be played on the first trick, unless the
+// player holds nothing else.
+func (r Rules) offFirstTrick() CardSet {
+	var off CardSet
+
+	if !r.HeartsOnFirstTrick {
+		off |= HeartsMask
+	}
+
+	if r.QueenOffFirstTrick {
+		off = off.Add(CardJamoke)
+	}
+
+	return off
+}
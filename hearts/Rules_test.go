@@ -0,0 +1,173 @@
+package hearts
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRulesDefaults(t *testing.T) {
+	h := New()
+
+	if h.Rules() != DefaultRules() {
+		t.Errorf("expected the default rules but received %+v", h.Rules())
+	}
+
+	if h.Rules().TargetScore != 100 || h.Rules().MoonShot != MoonShotAddToOthers {
+		t.Errorf("expected a standard game but received %+v", h.Rules())
+	}
+}
+
+func TestRulesTargetScore(t *testing.T) {
+	h := New(Options{Rules: Rules{TargetScore: 50}})
+
+	for p, score := range h.Score() {
+		if score != 50 {
+			t.Errorf("expected player %d to start 50 points from losing but they start at %d", p, score)
+		}
+	}
+}
+
+func TestRulesHeartsOnFirstTrick(t *testing.T) {
+	for _, allowed := range []bool{false, true} {
+		h := firstTrickGame(Rules{HeartsOnFirstTrick: allowed})
+		err := h.Play(PlayerTwo, 29)
+
+		if allowed && err != nil {
+			t.Errorf("expected a heart to be allowed on the first trick, but received: %s", err)
+		}
+
+		if !allowed && !errors.Is(err, ErrNoPointsOnFirstTrick) {
+			t.Errorf("expected a heart not to be allowed on the first trick, but received: %v", err)
+		}
+	}
+
+	// a player holding nothing but hearts has to play one of them
+	h := firstTrickGame(Rules{})
+	h.Players[PlayerTwo].Hand = []Card{29, 33}
+	checkLegalMoves(t, &h, PlayerTwo, 29, 33)
+	play(t, &h, PlayerTwo, false, 29)
+}
+
+// The standard rules let the Queen of Spades be played on the first trick, the same as
+// before Rules. Leagues that keep her off it set QueenOffFirstTrick.
+func TestRulesQueenOffFirstTrick(t *testing.T) {
+	h := firstTrickGame(Rules{})
+	play(t, &h, PlayerTwo, false, CardJamoke)
+
+	h = firstTrickGame(Rules{QueenOffFirstTrick: true, HeartsOnFirstTrick: true})
+
+	if err := h.Play(PlayerTwo, CardJamoke); !errors.Is(err, ErrNoPointsOnFirstTrick) {
+		t.Errorf("expected the Queen of Spades not to be allowed on the first trick, but received: %v", err)
+	}
+
+	// a player holding nothing but points has to play one of them
+	h = firstTrickGame(Rules{QueenOffFirstTrick: true})
+	h.Players[PlayerTwo].Hand = []Card{29, 33, CardJamoke}
+	checkLegalMoves(t, &h, PlayerTwo, 29, 33, CardJamoke)
+	play(t, &h, PlayerTwo, false, CardJamoke)
+}
+
+func TestRulesQueenBreaksHearts(t *testing.T) {
+	for _, breaks := range []bool{false, true} {
+		h := firstTrickGame(Rules{QueenBreaksHearts: breaks, HeartsOnFirstTrick: true})
+		play(t, &h, PlayerTwo, false, CardJamoke)
+
+		if h.brokenHearted != breaks {
+			t.Errorf("expected QueenBreaksHearts %t to leave hearts broken %t", breaks, breaks)
+		}
+	}
+
+	h := firstTrickGame(Rules{HeartsOnFirstTrick: true})
+	play(t, &h, PlayerTwo, false, 29)
+
+	if !h.brokenHearted {
+		t.Error("expected playing a heart to break hearts")
+	}
+}
+
+func TestRulesMoonShot(t *testing.T) {
+	tests := []struct {
+		moonShot MoonShot
+		shooter  int // points the shooter loses
+		others   int // points everyone else loses
+	}{
+		{MoonShotAddToOthers, 0, 26},
+		{MoonShotSubtractFromSelf, -26, 0},
+		{MoonShotNone, 26, 0},
+	}
+
+	for _, test := range tests {
+		h := setupCannedHands(handFinal)
+		h.rules = Rules{MoonShot: test.moonShot}.withDefaults()
+		h.phase = PhasePlay
+		h.lastTaken = PlayerTwo
+		h.trick = 12
+
+		for p := range h.Players {
+			h.Players[p].gameScore = 60
+		}
+
+		shooter := PlayerFour // player four holds the highest diamond
+		h.Players[shooter].roundScore = 24
+
+		play(t, &h, PlayerTwo, false, 1)
+		play(t, &h, PlayerOne, false, 28)
+		play(t, &h, PlayerFour, false, 3)
+		play(t, &h, PlayerThree, false, 30)
+
+		for p, score := range h.Score() {
+			lost := 60 - score
+			expected := test.others
+
+			if p == shooter {
+				expected = test.shooter
+			}
+
+			if lost != expected {
+				t.Errorf("%s: expected player %d to lose %d points, but they lost %d", test.moonShot, p, expected, lost)
+			}
+		}
+	}
+
+	// subtracting from a shooter never takes them past the target
+	h := setupCannedHands(handFinal)
+	h.rules = Rules{MoonShot: MoonShotSubtractFromSelf}.withDefaults()
+	h.phase = PhasePlay
+	h.lastTaken = PlayerTwo
+	h.trick = 12
+	h.Players[PlayerFour].roundScore = 24
+
+	play(t, &h, PlayerTwo, false, 1)
+	play(t, &h, PlayerOne, false, 28)
+	play(t, &h, PlayerFour, false, 3)
+	play(t, &h, PlayerThree, false, 30)
+
+	if h.Score()[PlayerFour] != 100 {
+		t.Errorf("expected the shooter to stay at 100 but they are at %d", h.Score()[PlayerFour])
+	}
+}
+
+func TestRulesPerspective(t *testing.T) {
+	rules := Rules{TargetScore: 75, QueenBreaksHearts: true, MoonShot: MoonShotNone}
+	h := New(Options{Rules: rules})
+	h.Setup()
+
+	per := perspective(t, &h, PlayerOne)
+
+	if per.Rules != rules {
+		t.Errorf("expected a player to see rules %+v but they see %+v", rules, per.Rules)
+	}
+}
+
+// firstTrickGame returns a game on the first trick in which player two is out of clubs
+// and is about to play, holding both hearts and the Queen of Spades.
+func firstTrickGame(rules Rules) Hearts {
+	h := setupCannedHands(handSmall)
+	h.rules = rules.withDefaults()
+	h.phase = PhasePlay
+	h.lastTaken = PlayerFour
+	h.Play(PlayerFour, 19)
+	h.Play(PlayerThree, 18)
+
+	return h
+}
@@ -0,0 +1,118 @@
+package hearts
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// RoundScore is one line of a ScoreSheet: how a round was scored. Scores on the sheet
+// count up the way they do on paper, from 0 toward the target score, rather than down
+// the way Score counts them.
+type RoundScore struct {
+
+	// Round is the round that was scored.
+	Round int `json:"round"`
+
+	// PassTo is the direction cards were passed in the round: `left`, `right`, `across`
+	// or `hold`.
+	PassTo string `json:"passTo"`
+
+	// Points are the points each player took in the round, by player index.
+	Points [4]int `json:"points"`
+
+	// Scored are the points each player's score went up by, by player index. They are the
+	// same as Points, unless someone shot the moon.
+	Scored [4]int `json:"scored"`
+
+	// Totals are each player's score after the round, by player index.
+	Totals [4]int `json:"totals"`
+
+	// MoonShot is the index of the player who shot the moon, or Nobody.
+	MoonShot int `json:"moonShot"`
+}
+
+// ScoreSheet is the score of every round of a game that has been played to the end, in
+// the order they were played.
+type ScoreSheet []RoundScore
+
+// JSONRoundScore is a RoundScore as it is shown to players. MoonShot is a player id, or 0
+// if nobody shot the moon.
+type JSONRoundScore struct {
+	Round    int    `json:"round"`
+	PassTo   string `json:"passTo"`
+	Points   []int  `json:"points"`
+	Scored   []int  `json:"scored"`
+	Totals   []int  `json:"totals"`
+	MoonShot int    `json:"moonShot,omitempty"`
+}
+
+// ScoreSheet returns the game's score sheet.
+func (h *Hearts) ScoreSheet() ScoreSheet {
+	return append(ScoreSheet{}, h.scoreSheet...)
+}
+
+// WriteCSV writes the score sheet as CSV, with a header row and then one row for each
+// round. Each row has the round, the pass direction, the points each player took, the
+// points each player's score went up by, each player's total afterward, and the id of the
+// player who shot the moon, if anyone did.
+func (s ScoreSheet) WriteCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+
+	header := []string{"round", "pass"}
+
+	for _, column := range []string{"points", "scored", "total"} {
+		for id := 1; id <= 4; id++ {
+			header = append(header, "player "+strconv.Itoa(id)+" "+column)
+		}
+	}
+
+	header = append(header, "moon shot")
+
+	if err := out.Write(header); err != nil {
+		return err
+	}
+
+	for _, r := range s {
+		row := []string{strconv.Itoa(r.Round), r.PassTo}
+
+		for _, column := range [][4]int{r.Points, r.Scored, r.Totals} {
+			for _, n := range column {
+				row = append(row, strconv.Itoa(n))
+			}
+		}
+
+		shooter := ""
+
+		if r.MoonShot != Nobody {
+			shooter = strconv.Itoa(r.MoonShot + 1)
+		}
+
+		if err := out.Write(append(row, shooter)); err != nil {
+			return err
+		}
+	}
+
+	out.Flush()
+
+	return out.Error()
+}
+
+// jsonScoreSheet returns the score sheet as it is shown to players.
+func jsonScoreSheet(sheet []RoundScore) []JSONRoundScore {
+	rounds := make([]JSONRoundScore, 0, len(sheet))
+
+	for i := range sheet {
+		r := sheet[i] // each round gets its own copy for its slices to point into
+		rounds = append(rounds, JSONRoundScore{
+			Round:    r.Round,
+			PassTo:   r.PassTo,
+			Points:   r.Points[:],
+			Scored:   r.Scored[:],
+			Totals:   r.Totals[:],
+			MoonShot: r.MoonShot + 1,
+		})
+	}
+
+	return rounds
+}
@@ -0,0 +1,156 @@
+package hearts
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestScoreSheet(t *testing.T) {
+	h := New(Options{Seed: 6})
+	h.Setup()
+
+	if sheet := h.ScoreSheet(); len(sheet) != 0 {
+		t.Fatalf("expected an empty score sheet before any round is played, but received %+v", sheet)
+	}
+
+	playRounds(t, &h, 5)
+	sheet := h.ScoreSheet()
+
+	if len(sheet) != 5 {
+		t.Fatalf("expected 5 rounds on the score sheet, but received %d", len(sheet))
+	}
+
+	totals := [4]int{}
+	directions := []string{"left", "right", "across", "hold", "left"}
+
+	for i, r := range sheet {
+		if r.Round != i+1 || r.PassTo != directions[i] {
+			t.Errorf("expected round %d passing %s, but received round %d passing %s", i+1, directions[i], r.Round, r.PassTo)
+		}
+
+		points := 0
+
+		for p := range r.Points {
+			points += r.Points[p]
+			totals[p] += r.Scored[p]
+
+			if r.MoonShot == Nobody && r.Scored[p] != r.Points[p] {
+				t.Errorf("round %d: expected player %d to score the %d points they took, but they scored %d", r.Round, p, r.Points[p], r.Scored[p])
+			}
+		}
+
+		if points != MoonPoints {
+			t.Errorf("round %d: expected 26 points to be taken, but %d were", r.Round, points)
+		}
+
+		if r.MoonShot != Nobody && r.Points[r.MoonShot] != MoonPoints {
+			t.Errorf("round %d: expected player %d to have taken every point to shoot the moon", r.Round, r.MoonShot)
+		}
+
+		if r.Totals != totals {
+			t.Errorf("round %d: expected running totals %v, but received %v", r.Round, totals, r.Totals)
+		}
+	}
+
+	for p, score := range h.Score() {
+		if sheet[4].Totals[p] != h.Rules().TargetScore-score {
+			t.Errorf("expected player %d's total to be %d, but it is %d", p, h.Rules().TargetScore-score, sheet[4].Totals[p])
+		}
+	}
+
+	// the sheet that was returned is a copy
+	sheet[0].Round = 99
+
+	if h.ScoreSheet()[0].Round != 1 {
+		t.Error("expected changing the returned sheet not to change the game's")
+	}
+}
+
+func TestScoreSheetCSV(t *testing.T) {
+	sheet := ScoreSheet{
+		{Round: 1, PassTo: "left", Points: [4]int{3, 10, 13, 0}, Scored: [4]int{3, 10, 13, 0}, Totals: [4]int{3, 10, 13, 0}, MoonShot: Nobody},
+		{Round: 2, PassTo: "right", Points: [4]int{0, 0, 26, 0}, Scored: [4]int{26, 26, 0, 26}, Totals: [4]int{29, 36, 13, 26}, MoonShot: PlayerThree},
+	}
+
+	var b bytes.Buffer
+
+	if err := sheet.WriteCSV(&b); err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	rows, err := csv.NewReader(&b).ReadAll()
+
+	if err != nil {
+		t.Fatalf("expected the sheet to be valid CSV but received: %s", err)
+	}
+
+	expected := [][]string{
+		{
+			"round", "pass",
+			"player 1 points", "player 2 points", "player 3 points", "player 4 points",
+			"player 1 scored", "player 2 scored", "player 3 scored", "player 4 scored",
+			"player 1 total", "player 2 total", "player 3 total", "player 4 total",
+			"moon shot",
+		},
+		{"1", "left", "3", "10", "13", "0", "3", "10", "13", "0", "3", "10", "13", "0", ""},
+		{"2", "right", "0", "0", "26", "0", "26", "26", "0", "26", "29", "36", "13", "26", "3"},
+	}
+
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("expected rows %v, but received %v", expected, rows)
+	}
+}
+
+func TestScoreSheetPerspective(t *testing.T) {
+	h := New(Options{Seed: 6})
+	h.Setup()
+	playRounds(t, &h, 2)
+
+	b, err := h.From(PlayerOne)
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	var per Perspective
+
+	if err := json.Unmarshal(b, &per); err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	sheet := h.ScoreSheet()
+
+	if len(per.ScoreSheet) != len(sheet) {
+		t.Fatalf("expected %d rounds on the score sheet, but received %d", len(sheet), len(per.ScoreSheet))
+	}
+
+	for i, r := range per.ScoreSheet {
+		if r.Round != sheet[i].Round || !compareSlices(r.Totals, sheet[i].Totals[:]) || r.MoonShot != sheet[i].MoonShot+1 {
+			t.Errorf("expected round %+v, but received %+v", sheet[i], r)
+		}
+	}
+
+	// the sheet is kept when the game is stored
+	stored, err := h.MarshalJSON()
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	var restored Hearts
+
+	if err := restored.UnmarshalJSON(stored); err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	if !reflect.DeepEqual(restored.ScoreSheet(), sheet) {
+		t.Errorf("expected the restored score sheet to be %+v, but received %+v", sheet, restored.ScoreSheet())
+	}
+
+	if clone := h.Clone(); !reflect.DeepEqual(clone.ScoreSheet(), sheet) {
+		t.Errorf("expected the cloned score sheet to be %+v, but received %+v", sheet, clone.ScoreSheet())
+	}
+}
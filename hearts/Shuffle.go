@@ -0,0 +1,58 @@
+package hearts
+
+import (
+	crand "crypto/rand"
+	"math/big"
+	"math/rand"
+)
+
+// Shuffler shuffles the deck before each deal. Every order of the deck must be equally
+// likely.
+type Shuffler interface {
+
+	// Shuffle puts the cards of the deck into a random order. The seed is the seed of
+	// the round being dealt. Shufflers that use it will deal the same hands each time a
+	// round is dealt; Shufflers that ignore it will not.
+	Shuffle(deck []Card, seed int64)
+}
+
+// SeededShuffler shuffles the deck with a Fisher-Yates shuffle driven by the round's
+// seed. It is the Shuffler games use unless they are given another one. Deals from a
+// SeededShuffler can always be regenerated from the game's seed.
+type SeededShuffler struct{}
+
+// Shuffle shuffles the deck using the seed.
+func (SeededShuffler) Shuffle(deck []Card, seed int64) {
+	r := rand.New(rand.NewSource(seed))
+	fisherYates(deck, r.Intn)
+}
+
+// CryptoShuffler shuffles the deck with a Fisher-Yates shuffle driven by crypto/rand. Its
+// deals can't be predicted by anyone who knows the game's seed, which makes it the
+// Shuffler to use for ranked play. The seed is ignored, so its deals can't be regenerated
+// either.
+type CryptoShuffler struct{}
+
+// Shuffle shuffles the deck using crypto/rand. It panics if the operating system's
+// source of randomness fails, since there is no safe way to deal without it.
+func (CryptoShuffler) Shuffle(deck []Card, seed int64) {
+	fisherYates(deck, func(n int) int {
+		i, err := crand.Int(crand.Reader, big.NewInt(int64(n)))
+
+		if err != nil {
+			panic("hearts: unable to read random numbers: " + err.Error())
+		}
+
+		return int(i.Int64())
+	})
+}
+
+// fisherYates shuffles the deck in place. intn must return a uniformly random number in
+// [0, n). Every card, including the one being looked at, can be picked for each spot,
+// which is what makes every order of the deck equally likely.
+func fisherYates(deck []Card, intn func(n int) int) {
+	for i := len(deck) - 1; i > 0; i-- {
+		j := intn(i + 1)
+		deck[i], deck[j] = deck[j], deck[i]
+	}
+}
@@ -0,0 +1,123 @@
+package hearts
+
+import (
+	"math"
+	"testing"
+)
+
+// zScore is how many standard deviations from the mean a chi-squared statistic has to be
+// before a test fails. 4.75 is roughly a one in a million chance of a false failure, so
+// the tests that use CryptoShuffler, which can't be seeded, won't flake.
+const zScore = 4.75
+
+func TestShufflePermutations(t *testing.T) {
+	shufflers := map[string]Shuffler{
+		"SeededShuffler": SeededShuffler{},
+		"CryptoShuffler": CryptoShuffler{},
+	}
+
+	for name, s := range shufflers {
+		t.Run(name, func(t *testing.T) {
+			checkPermutationsUniform(t, s)
+		})
+	}
+}
+
+func TestShufflePositions(t *testing.T) {
+	const shuffles = 52 * 1000
+	observed := make([]float64, 52*52)
+
+	for n := 0; n < shuffles; n++ {
+		deck := newDeck(52)
+		SeededShuffler{}.Shuffle(deck, roundSeed(1, n))
+
+		for position, card := range deck {
+			observed[int(card)*52+position]++
+		}
+	}
+
+	checkChiSquared(t, "card/position", observed, shuffles/52, 51*51)
+}
+
+func TestDealSeats(t *testing.T) {
+	shufflers := map[string]Shuffler{
+		"SeededShuffler": SeededShuffler{},
+		"CryptoShuffler": CryptoShuffler{},
+	}
+
+	for name, s := range shufflers {
+		t.Run(name, func(t *testing.T) {
+			const deals = 4000
+			observed := make([]float64, 52*4)
+			h := New(Options{Seed: 5, Shuffler: s})
+
+			for round := 1; round <= deals; round++ {
+				for seat, hand := range h.Deal(round) {
+					for _, card := range hand {
+						observed[int(card)*4+seat]++
+					}
+				}
+			}
+
+			checkChiSquared(t, "card/seat", observed, deals/4, 51*3)
+		})
+	}
+}
+
+// checkPermutationsUniform shuffles a four card deck many times and checks that each of
+// the 24 possible orders comes up equally often. A shuffle that can't leave a card where
+// it is would only ever produce 6 of them.
+func checkPermutationsUniform(t *testing.T, s Shuffler) {
+	const shuffles = 24 * 1000
+	observed := make([]float64, 4*4*4*4)
+
+	for n := 0; n < shuffles; n++ {
+		deck := newDeck(4)
+		s.Shuffle(deck, roundSeed(2, n))
+		observed[int(deck[0])*64+int(deck[1])*16+int(deck[2])*4+int(deck[3])]++
+	}
+
+	// only the 24 real orders should have been seen, so pick those out
+	perms := make([]float64, 0, 24)
+
+	for i, count := range observed {
+		a, b, c, d := i/64, i/16%4, i/4%4, i%4
+
+		if a != b && a != c && a != d && b != c && b != d && c != d {
+			perms = append(perms, count)
+		} else if count != 0 {
+			t.Fatalf("shuffling produced an impossible order: %d %d %d %d", a, b, c, d)
+		}
+	}
+
+	checkChiSquared(t, "permutation", perms, shuffles/24, 23)
+}
+
+// checkChiSquared fails the test if the observed counts are too far from the expected
+// count for the distribution to be called uniform. The critical value comes from the
+// Wilson-Hilferty approximation of the chi-squared distribution.
+func checkChiSquared(t *testing.T, name string, observed []float64, expected float64, df float64) {
+	t.Helper()
+	chi := 0.0
+
+	for _, o := range observed {
+		chi += (o - expected) * (o - expected) / expected
+	}
+
+	v := 2 / (9 * df)
+	critical := df * math.Pow(1-v+zScore*math.Sqrt(v), 3)
+
+	if chi > critical {
+		t.Errorf("%s counts are not uniform: chi-squared %.1f > %.1f (df %.0f)", name, chi, critical, df)
+	}
+}
+
+func newDeck(size int) []Card {
+	deck := make([]Card, size)
+
+	for i := range deck {
+		deck[i] = Card(i)
+	}
+
+	return deck
+}
@@ -0,0 +1,210 @@
+package hearts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrNoKibitzing is returned by Kibitz for a game that can't be kibitzed.
+var ErrNoKibitzing = errors.New("the game can't be kibitzed")
+
+// SpectatorView is the table as someone watching the game sees it. It shows everything
+// that has been played in the open, but none of the cards that are still in anyone's
+// hand, unless it is shown to a kibitzer.
+type SpectatorView struct {
+
+	// Broken is set to true if hearts have been broken.
+	Broken bool `json:"brokenHearted"`
+
+	// Finished is true once the game has ended.
+	Finished bool `json:"finished"`
+
+	// HandSizes are the number of cards each player is holding, from player 1 to player 4.
+	HandSizes []int `json:"handSizes"`
+
+	// Hands are every player's hand, from player 1 to player 4. They are only shown to
+	// kibitzers.
+	Hands [][]JSONCard `json:"hands,omitempty"`
+
+	// HasPassed are the ids of the players who have passed their cards this round.
+	HasPassed []int `json:"hasPassed,omitempty"`
+
+	// LastTrick are the cards played in the last trick, in seat order.
+	LastTrick []JSONCard `json:"lastTrick,omitempty"`
+
+	// Leader is the id of the player who led the current trick, or 0 until it is led.
+	Leader int `json:"leader,omitempty"`
+
+	// PassTo is the direction cards are passed this round: `left`, `right`, `across` or
+	// `hold`.
+	PassTo string `json:"passTo,omitempty"`
+
+	// Phase is either "pass" or "play".
+	Phase string `json:"phase"`
+
+	// Round is the round being played. It starts with 1.
+	Round int `json:"round"`
+
+	// Rules are the house rules the game is being played with.
+	Rules Rules `json:"rules"`
+
+	// Scores are each player's distance from losing, from player 1 to player 4, the same
+	// as Score returns.
+	Scores []int `json:"scores"`
+
+	// Suit is the suit that was led in the current trick.
+	Suit string `json:"suit,omitempty"`
+
+	// ThisTrick is the cards played into the current trick so far, in the order they were
+	// played.
+	ThisTrick []JSONCard `json:"thisTrick,omitempty"`
+
+	// Trick is the number of the trick being played in this round. It starts with 1.
+	Trick int `json:"trick"`
+
+	// Turn is the id of the player whose turn it is to play.
+	Turn int `json:"turn,omitempty"`
+
+	// Took is the id of the last player who took a trick.
+	Took int `json:"took,omitempty"`
+
+	// Winner are the ids of the players who won, once the game has finished.
+	Winner []int `json:"winner,omitempty"`
+}
+
+// Spectate returns the game as someone watching it sees it, as the JSON of a
+// SpectatorView. Unlike From, it doesn't need a seat, and it never shows a card that is
+// still in someone's hand.
+//
+// While the game's rules set a KibitzDelay, the game is shown as it was some tricks ago,
+// the same way it is shown to kibitzers, so that watching live can't be used to work out
+// what is left in the hands kibitzers see. SpectatorEvents are cut off at the same point.
+// A game whose log doesn't start with a deal, like one made by FromPosition, can't be
+// shown as it was, so it is shown as it is; such a game can't be kibitzed either.
+func (h *Hearts) Spectate() ([]byte, error) {
+	return h.spectate(false)
+}
+
+// Kibitz returns the game as a kibitzer sees it, as the JSON of a SpectatorView. It is the
+// same as Spectate, except that it shows every hand. Kibitzers see every card, so this must
+// never be shown to anyone playing the game. ErrNoKibitzing is returned unless the game's
+// rules set a KibitzDelay and its log starts with a deal.
+func (h *Hearts) Kibitz() ([]byte, error) {
+	if h.rules.KibitzDelay <= 0 {
+		return nil, fmt.Errorf("%w: its rules don't allow it", ErrNoKibitzing)
+	}
+
+	if !startsWithDeal(h.events) {
+		return nil, fmt.Errorf("%w: its log doesn't start with a deal", ErrNoKibitzing)
+	}
+
+	return h.spectate(true)
+}
+
+// SpectatorEvents returns the events in the game's log that spectators are allowed to
+// know about. While the game's rules set a KibitzDelay, the log is cut off at the game
+// Spectate shows. Otherwise, it is the whole log, the same as Events.
+func (h *Hearts) SpectatorEvents() []Event {
+	return h.Events()[:h.spectatorCut()]
+}
+
+// spectate returns the game as spectators see it, with every hand if hands is set.
+func (h *Hearts) spectate(hands bool) ([]byte, error) {
+	g := h
+
+	if cut := h.spectatorCut(); cut < len(h.events) {
+		delayed, err := Replay(h.events[:cut])
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to show the game as it was %d tricks ago: %w", h.rules.KibitzDelay, err)
+		}
+
+		g = &delayed
+	}
+
+	view := g.spectatorView()
+
+	if hands {
+		for _, p := range g.Players {
+			view.Hands = append(view.Hands, cardsToJSONCards(NewCardSet(p.Hand...).Cards()...))
+		}
+	}
+
+	return json.Marshal(view)
+}
+
+// spectatorView returns the game as it is, without anyone's hand.
+func (h *Hearts) spectatorView() SpectatorView {
+	view := SpectatorView{
+		Broken:    h.brokenHearted,
+		Finished:  h.finished,
+		HandSizes: make([]int, 0, 4),
+		HasPassed: playersToHasPassed(h.Players),
+		LastTrick: getLastTrick(h.trick, h.lastTrick),
+		Leader:    h.trickLeader() + 1,
+		PassTo:    roundToPassDirection(h.round),
+		Phase:     phaseToJSONPhase(h.phase),
+		Round:     h.round,
+		Rules:     h.rules,
+		Scores:    make([]int, 0, 4),
+		Suit:      h.suit,
+		ThisTrick: cardsToJSONCards(h.thisTrick()...),
+		Trick:     h.trick,
+		Took:      h.lastTaken + 1,
+		Winner:    playerIndicesToIDs(h.Winner()),
+	}
+
+	if h.phase == PhasePlay && !h.finished {
+		view.Turn = h.currentPlayer() + 1
+	}
+
+	for _, p := range h.Players {
+		view.HandSizes = append(view.HandSizes, len(p.Hand))
+		view.Scores = append(view.Scores, p.gameScore)
+	}
+
+	return view
+}
+
+// spectatorCut returns how many of the game's events spectators can see. While the game's
+// rules set a KibitzDelay, they see the game as it was when the trick that many tricks
+// before the one being played was about to be led, or as it was when this round was dealt
+// if the round isn't that far along yet. Once the game is finished, or if the log can't be
+// replayed, nothing is held back.
+func (h *Hearts) spectatorCut() int {
+	if h.rules.KibitzDelay <= 0 || h.finished || !startsWithDeal(h.events) {
+		return len(h.events)
+	}
+
+	deal := Nobody
+
+	for i := len(h.events) - 1; i >= 0; i-- {
+		if e := h.events[i]; e.Type == EventDeal && e.Round == h.round {
+			deal = i
+			break
+		}
+	}
+
+	if deal == Nobody {
+		return len(h.events)
+	}
+
+	trick := h.trick - h.rules.KibitzDelay
+	start := len(h.events)
+
+	for i := deal + 1; i < len(h.events); i++ {
+		e := h.events[i]
+
+		// the first move of the round
+		if start == len(h.events) && (e.Type == EventPass || e.Type == EventPlay) {
+			start = i
+		}
+
+		if e.Type == EventPlay && e.Trick == trick {
+			return i
+		}
+	}
+
+	return start
+}
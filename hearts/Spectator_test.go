@@ -0,0 +1,216 @@
+package hearts
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestSpectate(t *testing.T) {
+	h := New(Options{Seed: 2})
+	h.Setup()
+	playRounds(t, &h, 1)
+	passAll(t, &h)
+
+	for i := 0; i < 5; i++ {
+		p := h.PlayersTurn()[0]
+		play(t, &h, p, false, h.LegalMoves(p)[0])
+	}
+
+	view := spectate(t, &h)
+
+	if len(view.Hands) != 0 {
+		t.Errorf("expected spectators not to see any hands, but they see %v", view.Hands)
+	}
+
+	if view.Trick != 2 || len(view.ThisTrick) != 1 || len(view.LastTrick) != 4 {
+		t.Errorf("expected the second trick to have been led, but received %+v", view)
+	}
+
+	if view.Turn != h.PlayersTurn()[0]+1 || view.Leader != h.trickLeader()+1 {
+		t.Errorf("expected player %d's turn after player %d led, but received %+v", h.PlayersTurn()[0]+1, h.trickLeader()+1, view)
+	}
+
+	for p, size := range view.HandSizes {
+		if size != len(h.Players[p].Hand) {
+			t.Errorf("expected player %d to hold %d cards, but the spectator sees %d", p+1, len(h.Players[p].Hand), size)
+		}
+	}
+
+	if scores := h.Score(); len(view.Scores) != 4 || view.Scores[0] != scores[PlayerOne] || view.Scores[3] != scores[PlayerFour] {
+		t.Errorf("expected the scores %v but received %v", scores, view.Scores)
+	}
+}
+
+func TestSpectateKibitz(t *testing.T) {
+	h := New(Options{Seed: 2, Rules: Rules{KibitzDelay: 2}})
+	h.Setup()
+
+	var dealt [4]CardSet
+
+	for p, player := range h.Players {
+		dealt[p] = NewCardSet(player.Hand...)
+	}
+
+	passAll(t, &h)
+
+	var passed [4]CardSet
+
+	for p, player := range h.Players {
+		passed[p] = NewCardSet(player.Hand...)
+	}
+
+	firstTrick := CardSet(0)
+
+	for h.trick < 4 {
+		// until play is two tricks in, everyone watching sees the deal
+		if view := kibitz(t, &h); h.trick <= 2 && (view.Phase != "pass" || len(view.HasPassed) != 0 || !sameHands(view.Hands, dealt)) {
+			t.Fatalf("expected the hands as they were dealt during trick %d, but received %+v", h.trick, view)
+		}
+
+		p := h.PlayersTurn()[0]
+		c := h.LegalMoves(p)[0]
+
+		if h.trick == 1 {
+			firstTrick = firstTrick.Add(c)
+		}
+
+		play(t, &h, p, false, c)
+	}
+
+	// three tricks into the round, everything is shown as it was at the start of trick 2
+	view := kibitz(t, &h)
+
+	if view.Phase != "play" || view.Trick != 2 || len(view.ThisTrick) != 0 || len(view.LastTrick) != 4 {
+		t.Fatalf("expected the game as it was at trick 2, but received %+v", view)
+	}
+
+	for p := range passed {
+		passed[p] = passed[p].Remove(firstTrick.Cards()...)
+	}
+
+	if !sameHands(view.Hands, passed) {
+		t.Errorf("expected the hands %v but received %v", passed, view.Hands)
+	}
+
+	// spectators see the same game, without the hands
+	if spectated := spectate(t, &h); len(spectated.Hands) != 0 || spectated.Trick != 2 || len(spectated.ThisTrick) != 0 {
+		t.Errorf("expected the game as it was at trick 2 without any hands, but received %+v", spectated)
+	}
+
+	// and the events stop at the same place
+	events := h.SpectatorEvents()
+
+	for _, e := range events {
+		if e.Type == EventPlay && e.Trick >= 2 {
+			t.Fatalf("expected spectators not to see any plays from trick 2 on, but they see %+v", e)
+		}
+	}
+
+	if last := events[len(events)-1]; last.Type != EventTrickTaken || last.Trick != 1 {
+		t.Errorf("expected the spectators' log to end with the first trick being taken, but it ends with %+v", last)
+	}
+}
+
+func TestSpectateKibitzNotAllowed(t *testing.T) {
+	h := New(Options{Seed: 2})
+	h.Setup()
+
+	if _, err := h.Kibitz(); !errors.Is(err, ErrNoKibitzing) {
+		t.Errorf("expected %q but received %v", ErrNoKibitzing, err)
+	}
+
+	if len(h.SpectatorEvents()) != len(h.events) {
+		t.Error("expected spectators to see every event of a game without a kibitz delay")
+	}
+}
+
+func TestSpectateWithoutDeal(t *testing.T) {
+	h, err := FromPosition(Position{
+		Hands:       [4][]Card{{0, 4}, {1, 5}, {2, 6}, {3, 7}},
+		Leader:      PlayerOne,
+		TrickNumber: 12,
+		Round:       1,
+		Rules:       Rules{KibitzDelay: 1},
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	play(t, &h, PlayerOne, false, 0)
+
+	// the game can't be replayed to show it as it was, so spectators see it as it is
+	if view := spectate(t, &h); view.Trick != 12 || len(view.ThisTrick) != 1 {
+		t.Errorf("expected the twelfth trick to have been led, but received %+v", view)
+	}
+
+	if len(h.SpectatorEvents()) != len(h.events) {
+		t.Error("expected spectators to see every event of a game that can't be replayed")
+	}
+
+	if _, err := h.Kibitz(); !errors.Is(err, ErrNoKibitzing) {
+		t.Errorf("expected %q but received %v", ErrNoKibitzing, err)
+	}
+}
+
+func spectate(t *testing.T, h *Hearts) SpectatorView {
+	t.Helper()
+
+	var view SpectatorView
+	b, err := h.Spectate()
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	if err := json.Unmarshal(b, &view); err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	return view
+}
+
+func kibitz(t *testing.T, h *Hearts) SpectatorView {
+	t.Helper()
+
+	var view SpectatorView
+	b, err := h.Kibitz()
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	if err := json.Unmarshal(b, &view); err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	return view
+}
+
+// sameHands returns true if the hands shown are the expected ones.
+func sameHands(hands [][]JSONCard, expected [4]CardSet) bool {
+	if len(hands) != 4 {
+		return false
+	}
+
+	for p, hand := range hands {
+		shown := CardSet(0)
+
+		for _, c := range hand {
+			card, err := NewCard(c.Value, c.Suit)
+
+			if err != nil {
+				return false
+			}
+
+			shown = shown.Add(card)
+		}
+
+		if shown != expected[p] || len(hand) != len(expected[p].Cards()) {
+			return false
+		}
+	}
+
+	return true
+}
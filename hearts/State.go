@@ -0,0 +1,204 @@
+package hearts
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+)
+
+// stateVersion is the version of the format that Hearts is stored in. It must be bumped
+// whenever the format changes in a way that older stored games can't be read as-is, and
+// upgradeState must learn how to bring the older format up to date.
+const stateVersion = 3
+
+// state is the complete, storable form of Hearts. Unlike Hearts, all of its fields are
+// exported so that they survive encoding.
+type state struct {
+	Version       int            `json:"version"`
+	Players       [4]playerState `json:"players"`
+	BrokenHearted bool           `json:"brokenHearted"`
+	Finished      bool           `json:"finished"`
+	LastPlayed    int            `json:"lastPlayed"`
+	LastTaken     int            `json:"lastTaken"`
+	LastTrick     [4]Card        `json:"lastTrick"`
+	Phase         int            `json:"phase"`
+	PhaseEnd      bool           `json:"phaseEnd"`
+	Round         int            `json:"round"`
+	Rules         Rules          `json:"rules"`
+	Seed          int64          `json:"seed"`
+	Shuffler      string         `json:"shuffler,omitempty"`
+	Trick         int            `json:"trick"`
+	Suit          string         `json:"suit"`
+	Events        []Event        `json:"events,omitempty"`
+	Tricks        []Trick        `json:"tricks,omitempty"`
+	LastRound     []Trick        `json:"lastRound,omitempty"`
+	ScoreSheet    []RoundScore   `json:"scoreSheet,omitempty"`
+}
+
+// playerState is the complete, storable form of Player.
+type playerState struct {
+	Hand       []Card `json:"hand"`
+	Taken      []Card `json:"taken"`
+	Played     *Card  `json:"played"`
+	Receiving  []Card `json:"receiving"`
+	GameScore  int    `json:"gameScore"`
+	HasPassed  bool   `json:"hasPassed"`
+	RoundScore int    `json:"roundScore"`
+}
+
+// MarshalJSON encodes the complete state of the game, including the parts that players
+// aren't allowed to see. It is meant for storage; use From to show a game to a player. It
+// has a value receiver so that a Hearts value encodes the same way as a pointer to one.
+func (h Hearts) MarshalJSON() ([]byte, error) {
+	return json.Marshal(h.state())
+}
+
+// UnmarshalJSON restores a game that was encoded with MarshalJSON. Games stored by older
+// versions of this package are upgraded as they are read.
+func (h *Hearts) UnmarshalJSON(b []byte) error {
+	var s state
+
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	return h.restore(s)
+}
+
+// MarshalBinary encodes the complete state of the game in a compact binary form. It holds
+// the same information as MarshalJSON.
+func (h Hearts) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := gob.NewEncoder(&buf).Encode(h.state()); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary restores a game that was encoded with MarshalBinary.
+func (h *Hearts) UnmarshalBinary(b []byte) error {
+	var s state
+
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&s); err != nil {
+		return err
+	}
+
+	return h.restore(s)
+}
+
+// restore overwrites the game with the given state.
+func (h *Hearts) restore(s state) error {
+	s, err := upgradeState(s)
+
+	if err != nil {
+		return err
+	}
+
+	for i, p := range s.Players {
+		h.Players[i] = Player{
+			Hand:       nonNil(p.Hand),
+			Taken:      nonNil(p.Taken),
+			Played:     p.Played,
+			Receiving:  nonNil(p.Receiving),
+			gameScore:  p.GameScore,
+			hasPassed:  p.HasPassed,
+			roundScore: p.RoundScore,
+		}
+	}
+
+	h.brokenHearted = s.BrokenHearted
+	h.finished = s.Finished
+	h.lastPlayed = s.LastPlayed
+	h.lastTaken = s.LastTaken
+	h.lastTrick = s.LastTrick
+	h.phase = s.Phase
+	h.phaseEnd = s.PhaseEnd
+	h.round = s.Round
+	h.rules = s.Rules.withDefaults() // games stored before rules existed use the defaults
+	h.seed = s.Seed
+	h.shuffle = shufflerNamed(s.Shuffler)
+	h.trick = s.Trick
+	h.suit = s.Suit
+	h.events = s.Events
+	h.tricks = s.Tricks
+	h.lastRound = s.LastRound
+	h.scoreSheet = s.ScoreSheet
+
+	return nil
+}
+
+// state returns the storable form of the game.
+func (h *Hearts) state() state {
+	s := state{
+		Version:       stateVersion,
+		BrokenHearted: h.brokenHearted,
+		Finished:      h.finished,
+		LastPlayed:    h.lastPlayed,
+		LastTaken:     h.lastTaken,
+		LastTrick:     h.lastTrick,
+		Phase:         h.phase,
+		PhaseEnd:      h.phaseEnd,
+		Round:         h.round,
+		Rules:         h.rules,
+		Seed:          h.seed,
+		Shuffler:      h.shufflerName(),
+		Trick:         h.trick,
+		Suit:          h.suit,
+		Events:        h.events,
+		Tricks:        h.tricks,
+		LastRound:     h.lastRound,
+		ScoreSheet:    h.scoreSheet,
+	}
+
+	for i, p := range h.Players {
+		s.Players[i] = playerState{
+			Hand:       nonNil(p.Hand),
+			Taken:      nonNil(p.Taken),
+			Played:     p.Played,
+			Receiving:  nonNil(p.Receiving),
+			GameScore:  p.gameScore,
+			HasPassed:  p.hasPassed,
+			RoundScore: p.roundScore,
+		}
+	}
+
+	return s
+}
+
+// upgradeState brings a state stored by an older version of this package up to the
+// current version. States from a newer version can't be understood and return an error.
+func upgradeState(s state) (state, error) {
+	if s.Version < 1 || s.Version > stateVersion {
+		return s, fmt.Errorf("unable to read a game stored with version %d", s.Version)
+	}
+
+	// version 1 games were dealt from the clock. They are given a seed for the rounds
+	// they have left to play.
+	if s.Version < 2 {
+		s.Seed = newSeed(nil)
+	}
+
+	// version 2 games didn't keep a log, their tricks or a score sheet. They are kept from
+	// the next move on, so the game can't be replayed or have a move taken back, and the
+	// score sheet only has the rounds scored after the upgrade.
+	if s.Version < 3 {
+		s.Events, s.Tricks, s.LastRound, s.ScoreSheet = nil, nil, nil, nil
+	}
+
+	s.Version = stateVersion
+
+	return s, nil
+}
+
+// nonNil returns the given cards, or an empty slice if there aren't any. Empty slices
+// don't survive every encoding, so they are normalized on the way in and the way out.
+func nonNil(cards []Card) []Card {
+	if cards == nil {
+		return []Card{}
+	}
+
+	return cards
+}
@@ -0,0 +1,171 @@
+package hearts
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"testing"
+)
+
+func TestStateJSON(t *testing.T) {
+	h := midTrickGame(t)
+
+	b, err := h.MarshalJSON()
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	var restored Hearts
+
+	if err := restored.UnmarshalJSON(b); err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	checkSameState(t, &h, &restored)
+}
+
+func TestStateBinary(t *testing.T) {
+	h := midTrickGame(t)
+
+	b, err := h.MarshalBinary()
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	var restored Hearts
+
+	if err := restored.UnmarshalBinary(b); err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	checkSameState(t, &h, &restored)
+}
+
+func TestStateValue(t *testing.T) {
+	h := midTrickGame(t)
+
+	b, err := json.Marshal(h)
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	var restored Hearts
+
+	if err := json.Unmarshal(b, &restored); err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	checkSameState(t, &h, &restored)
+
+	var buf bytes.Buffer
+
+	if err := gob.NewEncoder(&buf).Encode(h); err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	restored = Hearts{}
+
+	if err := gob.NewDecoder(&buf).Decode(&restored); err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	checkSameState(t, &h, &restored)
+}
+
+func TestStateShuffler(t *testing.T) {
+	h := New(Options{Shuffler: CryptoShuffler{}})
+	b, _ := h.MarshalJSON()
+
+	var restored Hearts
+
+	if err := restored.UnmarshalJSON(b); err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	if _, ok := restored.shuffler().(CryptoShuffler); !ok {
+		t.Errorf("expected a CryptoShuffler but received %T", restored.shuffler())
+	}
+}
+
+func TestStateVersion(t *testing.T) {
+	var h Hearts
+
+	if err := h.UnmarshalJSON([]byte(`{"version": 0}`)); err == nil {
+		t.Error("expected an error for a state without a version")
+	}
+
+	if err := h.UnmarshalJSON([]byte(`{"version": 9999}`)); err == nil {
+		t.Error("expected an error for a state from a newer version")
+	}
+
+	// games from version 1 didn't have a seed
+	if err := h.UnmarshalJSON([]byte(`{"version": 1, "round": 2}`)); err != nil {
+		t.Errorf("expected no error but received: %s", err)
+	}
+
+	if h.Seed() == 0 {
+		t.Error("expected a version 1 game to be given a seed")
+	}
+
+	// games stored before rules existed are played by the default rules
+	if err := h.UnmarshalJSON([]byte(`{"version": 2, "round": 2}`)); err != nil {
+		t.Errorf("expected no error but received: %s", err)
+	}
+
+	if h.Rules() != DefaultRules() {
+		t.Errorf("expected a version 2 game to have the default rules, not %+v", h.Rules())
+	}
+
+	// games from version 2 didn't keep a log, so there is nothing to replay them from
+	if len(h.Events()) != 0 || len(h.ScoreSheet()) != 0 || len(h.Tricks()) != 0 {
+		t.Errorf("expected a version 2 game to have no log, score sheet or tricks")
+	}
+
+	if b, _ := h.MarshalJSON(); !bytes.Contains(b, []byte(`"version":3`)) {
+		t.Errorf("expected the upgraded game to be stored with version 3, but received %s", b)
+	}
+}
+
+// checkSameState fails the test if the two games would not be stored identically, or if
+// a player would see them differently.
+func checkSameState(t *testing.T, expected *Hearts, actual *Hearts) {
+	t.Helper()
+
+	expectedJSON, _ := expected.MarshalJSON()
+	actualJSON, _ := actual.MarshalJSON()
+
+	if !bytes.Equal(expectedJSON, actualJSON) {
+		t.Errorf("expected state %s but received %s", expectedJSON, actualJSON)
+	}
+
+	for p := range expected.Players {
+		expectedView, _ := expected.From(p)
+		actualView, _ := actual.From(p)
+
+		if !bytes.Equal(expectedView, actualView) {
+			t.Errorf("expected player %d to see %s but they see %s", p, expectedView, actualView)
+		}
+	}
+}
+
+// midTrickGame returns a game that has scores, broken hearts and half a trick played, so
+// that every part of the state has something interesting in it.
+func midTrickGame(t *testing.T) Hearts {
+	h := setupCannedHands(handSmall)
+	h.phase = PhasePlay
+	h.lastTaken = PlayerThree
+	h.trick = 7
+	h.brokenHearted = true
+	h.Players[PlayerOne].gameScore = 80
+	h.Players[PlayerTwo].roundScore = 3
+	h.Players[PlayerFour].hasPassed = true
+	h.lastTrick = [4]Card{0, 1, 2, 3}
+
+	play(t, &h, PlayerThree, false, card(h.Players[PlayerThree].Hand, SuitClubs))
+	play(t, &h, PlayerTwo, false, card(h.Players[PlayerTwo].Hand, SuitDiamonds))
+
+	return h
+}
@@ -0,0 +1,119 @@
+package hearts
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// tricksPerRound is the number of tricks in every round.
+const tricksPerRound = 13
+
+// Trick is a trick that has been played and taken.
+type Trick struct {
+
+	// Round is the round the trick was played in.
+	Round int `json:"round"`
+
+	// Number is the number of the trick in its round. It starts with 1.
+	Number int `json:"number"`
+
+	// Leader is the index of the player who led the trick.
+	Leader int `json:"leader"`
+
+	// Plays are the cards played into the trick, in the order they were played, starting
+	// with the Leader's.
+	Plays [4]PlayMove `json:"plays"`
+
+	// Winner is the index of the player who took the trick.
+	Winner int `json:"winner"`
+
+	// Points are the points in the trick.
+	Points int `json:"points"`
+}
+
+// JSONTrick is a Trick as it is shown to players, with players given by id.
+type JSONTrick struct {
+	Number int        `json:"number"`
+	Leader int        `json:"leader"`
+	Plays  []JSONPlay `json:"plays"`
+	Winner int        `json:"winner"`
+	Points int        `json:"points"`
+}
+
+// JSONPlay is a card played into a trick, and the id of the player who played it.
+type JSONPlay struct {
+	Seat int      `json:"seat"`
+	Card JSONCard `json:"card"`
+}
+
+// RoundReview is a look back at a round once it is over: every trick in the order it was
+// played, and what each player took.
+type RoundReview struct {
+
+	// Round is the round being reviewed.
+	Round int `json:"round"`
+
+	// Tricks are the round's tricks, in the order they were played.
+	Tricks []JSONTrick `json:"tricks"`
+
+	// Taken are the cards each player took, from player 1 to player 4.
+	Taken [][]JSONCard `json:"taken"`
+
+	// Points are the points each player took, from player 1 to player 4.
+	Points []int `json:"points"`
+
+	// MoonShot is the id of the player who shot the moon, or 0 if nobody did.
+	MoonShot int `json:"moonShot,omitempty"`
+}
+
+// Tricks returns the tricks that have been taken so far this round, in the order they were
+// played.
+func (h *Hearts) Tricks() []Trick {
+	return append([]Trick{}, h.tricks...)
+}
+
+// Review returns the JSON of a RoundReview of the last round that was played to the end.
+// An error is returned if no round has been played to the end yet.
+func (h *Hearts) Review() ([]byte, error) {
+	if len(h.lastRound) == 0 {
+		return nil, errors.New("no round has been played to the end yet")
+	}
+
+	review := RoundReview{
+		Round:  h.lastRound[0].Round,
+		Tricks: make([]JSONTrick, 0, len(h.lastRound)),
+		Taken:  make([][]JSONCard, len(h.Players)),
+		Points: make([]int, len(h.Players)),
+	}
+
+	for p := range review.Taken {
+		review.Taken[p] = []JSONCard{}
+	}
+
+	for _, t := range h.lastRound {
+		trick := JSONTrick{
+			Number: t.Number,
+			Leader: t.Leader + 1,
+			Plays:  make([]JSONPlay, 0, len(t.Plays)),
+			Winner: t.Winner + 1,
+			Points: t.Points,
+		}
+
+		for _, play := range t.Plays {
+			card := JSONCard{Suit: play.Card.Suit(), Value: play.Card.Value()}
+			trick.Plays = append(trick.Plays, JSONPlay{Seat: play.Seat + 1, Card: card})
+			review.Taken[t.Winner] = append(review.Taken[t.Winner], card)
+		}
+
+		review.Tricks = append(review.Tricks, trick)
+		review.Points[t.Winner] += t.Points
+	}
+
+	for p, points := range review.Points {
+		if points == MoonPoints {
+			review.MoonShot = p + 1
+		}
+	}
+
+	return json.Marshal(review)
+}
@@ -0,0 +1,305 @@
+package hearts
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTricks(t *testing.T) {
+	h := New(Options{Seed: 4})
+	h.Setup()
+	passAll(t, &h)
+
+	for trick := 1; trick <= 5; trick++ {
+		for i := 0; i < 4; i++ {
+			p := h.PlayersTurn()[0]
+			play(t, &h, p, false, h.LegalMoves(p)[0])
+		}
+
+		tricks := h.Tricks()
+
+		if len(tricks) != trick {
+			t.Fatalf("expected %d tricks to have been taken, but %d were", trick, len(tricks))
+		}
+
+		checkTrick(t, tricks[trick-1], 1, trick)
+	}
+
+	// every card in a trick goes to the player who took it
+	for _, trick := range h.Tricks() {
+		taker := h.Players[trick.Winner].Taken
+
+		for _, play := range trick.Plays {
+			if !hasCards(taker, play.Card) {
+				t.Errorf("expected player %d to have taken the %s", trick.Winner, play.Card)
+			}
+		}
+	}
+
+	taken := 0
+
+	for _, p := range h.Players {
+		taken += len(p.Taken)
+	}
+
+	if taken != 20 {
+		t.Errorf("expected 20 cards to have been taken, but %d were", taken)
+	}
+
+	// the tricks are kept when the game is stored
+	b, err := h.MarshalJSON()
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	var restored Hearts
+
+	if err := restored.UnmarshalJSON(b); err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	if !reflect.DeepEqual(restored.Tricks(), h.Tricks()) {
+		t.Error("expected the restored game to have the same tricks")
+	}
+}
+
+func TestReview(t *testing.T) {
+	h := New(Options{Seed: 4})
+	h.Setup()
+
+	if _, err := h.Review(); err == nil {
+		t.Error("expected an error before any round has been played")
+	}
+
+	playRounds(t, &h, 1)
+
+	if len(h.Tricks()) != 0 {
+		t.Errorf("expected no tricks in the new round, but there are %d", len(h.Tricks()))
+	}
+
+	for p, player := range h.Players {
+		if len(player.Taken) != 0 {
+			t.Errorf("expected player %d to have taken nothing in the new round, but they took %v", p, player.Taken)
+		}
+	}
+
+	b, err := h.Review()
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	var review RoundReview
+
+	if err := json.Unmarshal(b, &review); err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	if review.Round != 1 || len(review.Tricks) != 13 {
+		t.Fatalf("expected the 13 tricks of round 1, but received round %d with %d", review.Round, len(review.Tricks))
+	}
+
+	points, cards := 0, 0
+
+	for p := range review.Points {
+		points += review.Points[p]
+		cards += len(review.Taken[p])
+	}
+
+	if points != MoonPoints || cards != 52 {
+		t.Errorf("expected all 26 points in 52 cards to be taken, but %d were in %d", points, cards)
+	}
+
+	// the round's last trick is led by the winner of the trick before it
+	last, before := review.Tricks[12], review.Tricks[11]
+
+	if last.Leader != before.Winner || last.Plays[0].Seat != last.Leader {
+		t.Errorf("expected player %d to lead the last trick, but received %+v", before.Winner, last)
+	}
+}
+
+func TestTricksFromPosition(t *testing.T) {
+	start, plays := scriptedPlays(t)
+	game := start.Clone()
+
+	for _, m := range plays {
+		if err := game.Play(m.Seat, m.Card); err != nil {
+			t.Fatalf("expected no error but received: %s", err)
+		}
+	}
+
+	if tricks := game.Tricks(); len(tricks) != 12 {
+		t.Fatalf("expected 12 tricks but received %d", len(tricks))
+	}
+
+	for _, trick := range game.Tricks() {
+		checkTrick(t, trick, 4, trick.Number)
+	}
+}
+
+// checkTrick checks that the trick was played in turn, and taken by the highest card of
+// the suit that was led.
+func checkTrick(t *testing.T, trick Trick, round int, number int) {
+	t.Helper()
+
+	if trick.Round != round || trick.Number != number || trick.Leader != trick.Plays[0].Seat {
+		t.Errorf("expected trick %d of round %d, led by the first to play, but received %+v", number, round, trick)
+	}
+
+	winner := trick.Plays[0]
+	cards := [4]Card{}
+
+	for i, play := range trick.Plays {
+		if i > 0 && play.Seat != nextPlayer(trick.Plays[i-1].Seat) {
+			t.Errorf("expected the trick to be played in turn, but received %+v", trick.Plays)
+		}
+
+		if play.Card.Suit() == winner.Card.Suit() && play.Card > winner.Card {
+			winner = play
+		}
+
+		cards[i] = play.Card
+	}
+
+	if trick.Winner != winner.Seat || trick.Points != sumTrickPoints(cards) {
+		t.Errorf("expected player %d to take %d points, but received %+v", winner.Seat, sumTrickPoints(cards), trick)
+	}
+}
+
+func TestTrickLifecycle(t *testing.T) {
+	h := scriptedTricks(t, Rules{})
+
+	// hearts can't be led before they are broken
+	checkPlayError(t, &h, PlayerOne, 28, ErrHeartsNotBroken)
+
+	// leading sets the suit
+	play(t, &h, PlayerOne, false, 3)
+	checkTransition(t, &h, EventTrickLed, PlayerOne, 3)
+
+	if h.suit != SuitDiamonds {
+		t.Fatalf("expected diamonds to have been led, but the suit is %q", h.suit)
+	}
+
+	checkPlayError(t, &h, PlayerFour, 14, ErrMustFollowSuit)
+	play(t, &h, PlayerFour, false, 7)
+
+	// a heart from a player who can't follow breaks hearts, but doesn't change the suit
+	play(t, &h, PlayerThree, false, 33)
+	checkTransition(t, &h, EventHeartsBroken, PlayerThree, 33)
+
+	if !h.brokenHearted || h.suit != SuitDiamonds {
+		t.Fatalf("expected hearts to be broken with diamonds still led, but received %v and %q", h.brokenHearted, h.suit)
+	}
+
+	// resolving the trick clears the suit for the next one
+	play(t, &h, PlayerTwo, false, 0)
+
+	if last := h.events[len(h.events)-1]; last.Type != EventTrickTaken || last.Seat != PlayerFour || last.Points != 1 {
+		t.Fatalf("expected player 4 to take the trick with a point in it, but received %+v", last)
+	}
+
+	if h.suit != "" || h.trick != 12 {
+		t.Fatalf("expected trick 12 not to have been led, but received trick %d of %q", h.trick, h.suit)
+	}
+
+	// hearts can be led once they are broken, and are then the suit to follow
+	play(t, &h, PlayerFour, false, 26)
+	checkTransition(t, &h, EventTrickLed, PlayerFour, 26)
+	play(t, &h, PlayerThree, false, 16)
+	play(t, &h, PlayerTwo, false, 43)
+	checkPlayError(t, &h, PlayerOne, 20, ErrMustFollowSuit)
+	play(t, &h, PlayerOne, false, 28)
+
+	if last := h.events[len(h.events)-1]; last.Type != EventTrickTaken || last.Seat != PlayerOne {
+		t.Fatalf("expected player 1 to take the hearts trick, but received %+v", last)
+	}
+
+	// and the next trick is led with a new suit
+	play(t, &h, PlayerOne, false, 20)
+	checkTransition(t, &h, EventTrickLed, PlayerOne, 20)
+
+	count := 0
+
+	for _, e := range h.events {
+		if e.Type == EventHeartsBroken {
+			count++
+		}
+	}
+
+	if count != 1 {
+		t.Errorf("expected hearts to be broken once, but they were broken %d times", count)
+	}
+}
+
+func TestQueenBreaksHearts(t *testing.T) {
+	for _, rules := range []Rules{{}, {QueenBreaksHearts: true}} {
+		h := scriptedTricks(t, rules)
+
+		play(t, &h, PlayerOne, false, 3)
+		play(t, &h, PlayerFour, false, 7)
+
+		// discarding the Queen only breaks hearts under the house rule
+		h.Players[PlayerThree].Hand = []Card{16, 33, CardJamoke}
+		play(t, &h, PlayerThree, false, CardJamoke)
+
+		if h.brokenHearted != rules.QueenBreaksHearts {
+			t.Errorf("expected hearts broken to be %v with %+v", rules.QueenBreaksHearts, rules)
+		}
+
+		if rules.QueenBreaksHearts {
+			checkTransition(t, &h, EventHeartsBroken, PlayerThree, CardJamoke)
+		} else if last := h.events[len(h.events)-1]; last.Type != EventPlay {
+			t.Errorf("expected only the play to be logged, but received %+v", last)
+		}
+	}
+}
+
+// scriptedTricks returns a game at trick 11 of a round, with player 1 to lead. Player 3 is
+// out of diamonds and hearts haven't been broken.
+func scriptedTricks(t *testing.T, rules Rules) Hearts {
+	t.Helper()
+
+	h, err := FromPosition(Position{
+		Hands: [4][]Card{
+			{3, 20, 28},  // Five of Diamonds, Nine of Clubs, Four of Hearts
+			{0, 25, 43},  // Two of Diamonds, Ace of Clubs, Six of Spades
+			{16, 33, 41}, // Five of Clubs, Nine of Hearts, Four of Spades
+			{7, 14, 26},  // Nine of Diamonds, Three of Clubs, Two of Hearts
+		},
+		Leader:      PlayerOne,
+		TrickNumber: 11,
+		Round:       1,
+		Rules:       rules,
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	return h
+}
+
+// checkTransition checks that the last event logged is the transition, caused by the card.
+func checkTransition(t *testing.T, h *Hearts, expected EventType, seat int, card Card) {
+	t.Helper()
+	e := h.events[len(h.events)-1]
+
+	if e.Type != expected || e.Seat != seat || e.Card == nil || *e.Card != card || e.Trick != h.trick {
+		t.Errorf("expected %s by player %d with the %s, but received %+v", expected, seat, card, e)
+	}
+
+	if expected == EventTrickLed && e.Suit != card.Suit() {
+		t.Errorf("expected %s to be led, but received %q", card.Suit(), e.Suit)
+	}
+}
+
+func checkPlayError(t *testing.T, h *Hearts, player int, card Card, expected error) {
+	t.Helper()
+
+	if err := h.Play(player, card); !errors.Is(err, expected) {
+		t.Errorf("expected %q but received %v", expected, err)
+	}
+}
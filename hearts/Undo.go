@@ -0,0 +1,112 @@
+package hearts
+
+// Undo takes back the last pass or play made in the game for the player, if the rules allow
+// takebacks. Players can only take back their own moves, and only if nobody has moved
+// since.
+// The game is rebuilt from its events without the move, so everything the move did is
+// reversed with it: the led suit, broken hearts, and any trick, pass or round that it
+// completed.
+//
+// The events the move logged are cut from the end of the log, and the moves made after
+// the takeback are logged in their place. Anything that identifies events by their place
+// in the log, like the ids of a Server-Sent Events stream, has to start again from the
+// beginning of the log after a move is taken back.
+//
+// A move that only put a card on the table can be taken back on its own. A move that
+// completed a trick or resolved the passes has already been seen by the whole table, so
+// it can only be taken back if every seat agrees; agreeing are the indices of the seats
+// that agree to it.
+//
+// A *RuleError is returned if the rules don't allow takebacks, if there is no move to take
+// back or no deal to rebuild the game from, if the last move wasn't the player's, or if a
+// move can't be taken back without everyone's agreement.
+func (h *Hearts) Undo(player int, agreeing ...int) error {
+	if !h.rules.Takebacks {
+		return ruleError(CodeNoTakebacks, Nobody, nil, "the rules don't allow moves to be taken back")
+	}
+
+	last := lastMove(h.events)
+
+	// a game that wasn't dealt by this package, like one made by FromPosition, can't be
+	// rebuilt from its events
+	if last == Nobody || !startsWithDeal(h.events) {
+		return ruleError(CodeNothingToUndo, Nobody, nil, "there is no move to take back")
+	}
+
+	move := h.events[last]
+	cards := move.Cards
+
+	if move.Card != nil {
+		cards = []Card{*move.Card}
+	}
+
+	if move.Seat != player {
+		return ruleError(
+			CodeNotYourMove,
+			player,
+			nil,
+			"player %d can't take back player %d's move",
+			player+1,
+			move.Seat+1,
+		)
+	}
+
+	// anything logged after the move, besides what the play itself changed, was completed
+	// by it
+	for _, e := range h.events[last+1:] {
+		if !e.Type.partOfPlay() && !allAgree(agreeing) {
+			return ruleError(
+				CodeUndoNeedsAgreement,
+				move.Seat,
+				cards,
+				"every seat must agree to take back a move that completed a %s",
+				completed(e),
+			)
+		}
+	}
+
+	undone, err := Replay(h.events[:last])
+
+	if err != nil {
+		return err
+	}
+
+	undone.shuffle = h.shuffle
+	*h = undone
+
+	return nil
+}
+
+// allAgree returns true if every seat is among the agreeing seats.
+func allAgree(agreeing []int) bool {
+	seats := [4]bool{}
+
+	for _, p := range agreeing {
+		if p >= PlayerOne && p <= PlayerFour {
+			seats[p] = true
+		}
+	}
+
+	return seats == [4]bool{true, true, true, true}
+}
+
+// completed describes what a move completed, from the first event that followed it.
+func completed(e Event) string {
+	if e.Type == EventPassesResolved {
+		return "pass"
+	}
+
+	return "trick"
+}
+
+// lastMove returns the index of the last pass or play in the events, or Nobody if there
+// isn't one.
+func lastMove(events []Event) int {
+	for i := len(events) - 1; i >= 0; i-- {
+		if events[i].Type == EventPass || events[i].Type == EventPlay {
+			return i
+		}
+	}
+
+	return Nobody
+}
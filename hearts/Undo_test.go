@@ -0,0 +1,176 @@
+package hearts
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestUndoNotAllowed(t *testing.T) {
+	h := New(Options{Seed: 2})
+	h.Setup()
+	playRounds(t, &h, 1)
+
+	if err := h.Undo(PlayerOne); !errors.Is(err, ErrNoTakebacks) {
+		t.Errorf("expected a %s error but received: %v", CodeNoTakebacks, err)
+	}
+
+	h = New(Options{Seed: 2, Rules: Rules{Takebacks: true}})
+	h.Setup()
+
+	if err := h.Undo(PlayerOne); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("expected a %s error but received: %v", CodeNothingToUndo, err)
+	}
+}
+
+func TestUndoWithoutDeal(t *testing.T) {
+	h, err := FromPosition(Position{
+		Hands:       [4][]Card{{0}, {1}, {2}, {3}},
+		Leader:      PlayerOne,
+		TrickNumber: 13,
+		Round:       1,
+		Rules:       Rules{Takebacks: true},
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	play(t, &h, PlayerOne, false, 0)
+
+	// the game wasn't dealt, so it can't be rebuilt without the play
+	if err := h.Undo(PlayerOne); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("expected a %s error but received: %v", CodeNothingToUndo, err)
+	}
+}
+
+func TestUndoPlays(t *testing.T) {
+	h := New(Options{Seed: 2, Rules: Rules{Takebacks: true}})
+	h.Setup()
+	passAll(t, &h)
+
+	leads, breaks := 0, 0
+
+	// take back every play that doesn't finish a trick, then play it again
+	for h.Round() == 1 {
+		p := h.PlayersTurn()[0]
+		c := h.LegalMoves(p)[0]
+		before, _ := h.MarshalJSON()
+		broken := h.brokenHearted
+		leading := h.suit == ""
+
+		if err := h.Play(p, c); err != nil {
+			t.Fatalf("expected no error but received: %s", err)
+		}
+
+		if h.lastPlayed == Nobody { // the play finished the trick
+			continue
+		}
+
+		if leading {
+			leads++
+		}
+
+		if !broken && h.brokenHearted {
+			breaks++
+		}
+
+		// nobody else can take the play back
+		if err := h.Undo(nextPlayer(p)); !errors.Is(err, ErrNotYourMove) {
+			t.Fatalf("expected a %s error but received: %v", CodeNotYourMove, err)
+		}
+
+		if err := h.Undo(p); err != nil {
+			t.Fatalf("expected no error but received: %s", err)
+		}
+
+		after, _ := h.MarshalJSON()
+
+		if !bytes.Equal(before, after) {
+			t.Fatalf("expected taking back the %s to restore\n%s\nbut the game is\n%s", c, before, after)
+		}
+
+		if err := h.Play(p, c); err != nil {
+			t.Fatalf("expected no error but received: %s", err)
+		}
+	}
+
+	if leads == 0 || breaks == 0 {
+		t.Errorf("expected to take back leads and hearts being broken, but took back %d leads and %d breaks", leads, breaks)
+	}
+}
+
+func TestUndoNeedsAgreement(t *testing.T) {
+	h := New(Options{Seed: 2, Rules: Rules{Takebacks: true}})
+	h.Setup()
+	beforePasses, _ := h.MarshalJSON()
+	passAll(t, &h)
+
+	// the last pass resolved the passes
+	checkUndoNeedsAgreement(t, &h)
+
+	// with the last pass taken back, the others can be taken back on their own
+	for i := 0; i < 3; i++ {
+		if err := h.Undo(h.events[lastMove(h.events)].Seat); err != nil {
+			t.Fatalf("expected no error but received: %s", err)
+		}
+	}
+
+	after, _ := h.MarshalJSON()
+
+	if !bytes.Equal(beforePasses, after) {
+		t.Errorf("expected every pass to be taken back, but the game is\n%s", after)
+	}
+
+	passAll(t, &h)
+
+	for h.trick == 1 {
+		p := h.PlayersTurn()[0]
+
+		if err := h.Play(p, h.LegalMoves(p)[0]); err != nil {
+			t.Fatalf("expected no error but received: %s", err)
+		}
+	}
+
+	// the last play took the first trick
+	checkUndoNeedsAgreement(t, &h)
+
+	if h.trick != 1 || h.suit == "" || len(h.thisTrick()) != 3 {
+		t.Errorf("expected to be back in the first trick with three cards played, but found %v", h.thisTrick())
+	}
+}
+
+// checkUndoNeedsAgreement checks that the last move can only be taken back once every seat
+// agrees.
+func checkUndoNeedsAgreement(t *testing.T, h *Hearts) {
+	t.Helper()
+	events := len(h.events)
+	seat := h.events[lastMove(h.events)].Seat
+
+	for _, agreeing := range [][]int{nil, {PlayerOne, PlayerTwo, PlayerThree}, {PlayerOne, PlayerOne, PlayerTwo, 7}} {
+		if err := h.Undo(seat, agreeing...); !errors.Is(err, ErrUndoNeedsAgreement) {
+			t.Errorf("expected a %s error with %v agreeing, but received: %v", CodeUndoNeedsAgreement, agreeing, err)
+		}
+	}
+
+	if len(h.events) != events {
+		t.Fatal("expected a refused takeback to leave the game alone")
+	}
+
+	if err := h.Undo(seat, PlayerOne, PlayerTwo, PlayerThree, PlayerFour); err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+}
+
+// passAll has every player pass their first three cards.
+func passAll(t *testing.T, h *Hearts) {
+	t.Helper()
+
+	for h.Phase() == PhasePass {
+		p := h.PlayersTurn()[0]
+
+		if err := h.Play(p, h.Players[p].Hand[:3]...); err != nil {
+			t.Fatalf("expected no error but received: %s", err)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package hearts
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+)
 
 type JSONCard struct {
 	Suit  string `json:"suit"`
@@ -19,30 +22,82 @@ type Perspective struct {
 	// Hand is the hand of the player being viewed.
 	Hand []JSONCard `json:"hand"`
 
+	// HandSizes are the number of cards each player is holding, from player 1 to player 4.
+	HandSizes []int `json:"handSizes"`
+
 	// HasPassed is a slice of player ids representing the players who have passed their
 	// cards during the passing phase.
 	HasPassed []int `json:"hasPassed,omitempty"`
 
-	// LastTrick are the cards played in the last trick
+	// Legal is the cards in Hand that the player is allowed to play right now. It is
+	// empty when it isn't the player's turn.
+	Legal []JSONCard `json:"legal"`
+
+	// LastTrick are the cards played in the last trick, in seat order: the first card was
+	// played by player 1, the second by player 2, and so on.
 	LastTrick []JSONCard `json:"lastTrick,omitempty"`
 
+	// LastTrickPoints are the points in LastTrick.
+	LastTrickPoints int `json:"lastTrickPoints,omitempty"`
+
+	// Passed are the cards the player passed this round, once they have passed them.
+	Passed []JSONCard `json:"passed,omitempty"`
+
+	// PassedTo is the id of the player that the player passes to this round. It is 0 in
+	// rounds when cards are held.
+	PassedTo int `json:"passedTo,omitempty"`
+
 	// PassTo is a string which can either be `left`, `right`, `across` or `hold`.
 	PassTo string `json:"passTo,omitempty"`
 
+	// Leader is the id of the player who led the current trick. It is 0 until the trick
+	// has been led.
+	Leader int `json:"leader,omitempty"`
+
 	// Phase is an int that represents the Phase of the game. There are two phases in
 	// Hearts, the pass Phase (which is 0) and the play Phase (which is 1).
 	Phase string `json:"phase"`
 
+	// PointsToLose are each player's distance from losing, from player 1 to player 4, the
+	// same as Score returns. They count down, where the Totals on the ScoreSheet count up.
+	PointsToLose []int `json:"pointsToLose"`
+
+	// Received are the cards the player was passed this round. They are only shown once
+	// every player has passed and the cards have been added to the player's Hand.
+	Received []JSONCard `json:"received,omitempty"`
+
+	// ReceivedFrom is the id of the player who passes to the player this round. It is 0
+	// in rounds when cards are held.
+	ReceivedFrom int `json:"receivedFrom,omitempty"`
+
 	// Round is the Round number that is currently being played. Round starts with 1.
 	Round int `json:"round"`
 
+	// RoundPoints are the points each player has taken so far this round, from player 1
+	// to player 4.
+	RoundPoints []int `json:"roundPoints"`
+
+	// Rules are the house rules the game is being played with.
+	Rules Rules `json:"rules"`
+
+	// ScoreSheet is how every round that has been played to the end was scored, in the
+	// order they were played.
+	ScoreSheet []JSONRoundScore `json:"scoreSheet"`
+
+	// Seat is the id of the player whose perspective this is.
+	Seat int `json:"seat"`
+
 	// suit is the suit of the first card played into the trick. It is the suit that must
 	// be followed.
 	Suit string `json:"suit,omitempty"`
 
-	// ThisTrick is the cards that have been played into the trick so far
+	// ThisTrick is the cards that have been played into the trick so far, in the order
+	// they were played, starting with the Leader's card.
 	ThisTrick []JSONCard `json:"thisTrick,omitempty"`
 
+	// Trick is the number of the trick being played in this round. It starts with 1.
+	Trick int `json:"trick"`
+
 	// Turn is the id of the player whose turn it is.
 	Turn int `json:"turn,omitempty"`
 
@@ -53,21 +108,53 @@ type Perspective struct {
 	Winner []int `json:"winner,omitempty"`
 }
 
+// From returns the game as the player sees it, as the JSON of a Perspective. An error is
+// returned if there is no such player; people watching the game can see it with Spectate
+// instead.
 func (h *Hearts) From(player int) ([]byte, error) {
+	if player < PlayerOne || player > PlayerFour {
+		return nil, fmt.Errorf("there is no player %d at the table", player)
+	}
+
 	per := Perspective{
-		Broken:    h.brokenHearted,
-		Finished:  h.finished,
-		Hand:      cardsToJSONCards(h.Players[player].Hand...),
-		HasPassed: playersToHasPassed(h.Players),
-		LastTrick: getLastTrick(h.trick, h.lastTrick),
-		PassTo:    roundToPassDirection(h.round),
-		Phase:     phaseToJSONPhase(h.phase),
-		Round:     h.round,
-		Suit:      h.suit,
-		ThisTrick: playersToThisTrick(h.Players),
-		Turn:      getToTurn(h.Phase(), h.PlayersTurn()),
-		Took:      h.lastTaken + 1,
-		Winner:    playerIndicesToIDs(h.Winner()),
+		Broken:       h.brokenHearted,
+		Finished:     h.finished,
+		Hand:         cardsToJSONCards(h.Players[player].Hand...),
+		HandSizes:    make([]int, 0, 4),
+		HasPassed:    playersToHasPassed(h.Players),
+		Legal:        cardsToJSONCards(h.LegalMoves(player)...),
+		LastTrick:    getLastTrick(h.trick, h.lastTrick),
+		Leader:       h.trickLeader() + 1,
+		PassedTo:     PassTarget(h.round, player) + 1,
+		PassTo:       roundToPassDirection(h.round),
+		Phase:        phaseToJSONPhase(h.phase),
+		PointsToLose: make([]int, 0, 4),
+		ReceivedFrom: passGiver(h.round, player) + 1,
+		Round:        h.round,
+		RoundPoints:  make([]int, 0, 4),
+		Rules:        h.rules,
+		ScoreSheet:   jsonScoreSheet(h.scoreSheet),
+		Seat:         player + 1,
+		Suit:         h.suit,
+		ThisTrick:    cardsToJSONCards(h.thisTrick()...),
+		Trick:        h.trick,
+		Turn:         getToTurn(h.Phase(), h.PlayersTurn()),
+		Took:         h.lastTaken + 1,
+		Winner:       playerIndicesToIDs(h.Winner()),
+	}
+
+	passed, received := h.passes(player)
+	per.Passed = cardsToJSONCards(passed...)
+	per.Received = cardsToJSONCards(received...)
+
+	if h.trick > 1 {
+		per.LastTrickPoints = sumTrickPoints(h.lastTrick)
+	}
+
+	for _, p := range h.Players {
+		per.HandSizes = append(per.HandSizes, len(p.Hand))
+		per.RoundPoints = append(per.RoundPoints, p.roundScore)
+		per.PointsToLose = append(per.PointsToLose, p.gameScore)
 	}
 
 	b, err := json.Marshal(per)
@@ -79,6 +166,49 @@ func (h *Hearts) From(player int) ([]byte, error) {
 	return b, nil
 }
 
+// passes returns the cards the player passed this round, and the cards they were passed
+// once every player has passed. They are found in the game's log, so nothing is returned
+// for passes the log doesn't go back to.
+func (h *Hearts) passes(player int) (passed []Card, received []Card) {
+	from := passGiver(h.round, player)
+	resolved := false
+
+	for i := len(h.events) - 1; i >= 0; i-- {
+		e := h.events[i]
+
+		if e.Round != h.round || e.Type == EventDeal {
+			break
+		}
+
+		switch {
+		case e.Type == EventPass && e.Seat == player:
+			passed = e.Cards
+		case e.Type == EventPass && e.Seat == from:
+			received = e.Cards
+		case e.Type == EventPassesResolved:
+			resolved = true
+		}
+	}
+
+	if !resolved {
+		received = nil
+	}
+
+	return passed, received
+}
+
+// passGiver returns the index of the player who passes to the given player in the given
+// round, or Nobody in rounds when cards are held.
+func passGiver(round int, player int) int {
+	for p := PlayerOne; p <= PlayerFour; p++ {
+		if PassTarget(round, p) == player {
+			return p
+		}
+	}
+
+	return Nobody
+}
+
 func cardsToJSONCards(cards ...Card) []JSONCard {
 	JSONCards := make([]JSONCard, 0, 13)
 
@@ -126,18 +256,6 @@ func playersToHasPassed(players [4]Player) []int {
 	return hasPassed
 }
 
-func playersToThisTrick(players [4]Player) []JSONCard {
-	trick := make([]Card, 0, 3)
-
-	for _, player := range players {
-		if player.Played != nil {
-			trick = append(trick, *player.Played)
-		}
-	}
-
-	return cardsToJSONCards(trick...)
-}
-
 func roundToPassDirection(round int) string {
 	switch round % 4 {
 	case 1:
@@ -1,7 +1,9 @@
 package hearts
 
 import (
+	"encoding/json"
 	"fmt"
+	"reflect"
 	"testing"
 )
 
@@ -23,3 +25,188 @@ func TestFrom(t *testing.T) {
 		t.Error("expected a byte array, but received nil")
 	}
 }
+
+func TestFromLegal(t *testing.T) {
+	h := setupCannedHands(handFull)
+	h.phase = PhasePlay
+
+	per := perspective(t, &h, PlayerTwo)
+
+	if len(per.Legal) != 1 || per.Legal[0] != (JSONCard{Suit: SuitClubs, Value: "Two"}) {
+		t.Errorf("expected the Two of Clubs to be the only legal card, but received %v", per.Legal)
+	}
+
+	if per := perspective(t, &h, PlayerOne); len(per.Legal) != 0 {
+		t.Errorf("expected no legal cards when it isn't the player's turn, but received %v", per.Legal)
+	}
+}
+
+func TestFromThisTrick(t *testing.T) {
+	h := setupCannedHands(handFull)
+	h.phase = PhasePlay
+
+	play(t, &h, PlayerTwo, false, CardTwoOfClubs)
+	play(t, &h, PlayerOne, false, 24)
+	play(t, &h, PlayerFour, false, 15)
+
+	per := perspective(t, &h, PlayerThree)
+	expected := cardsToJSONCards(CardTwoOfClubs, 24, 15)
+
+	if len(per.ThisTrick) != 3 || per.ThisTrick[1] != expected[1] || per.ThisTrick[2] != expected[2] {
+		t.Errorf("expected the trick in play order %v but received %v", expected, per.ThisTrick)
+	}
+
+	if per.Leader != PlayerTwo+1 || per.Seat != PlayerThree+1 || per.Trick != 1 {
+		t.Errorf("expected player 2 to lead trick 1 for player 3, but received %+v", per)
+	}
+
+	play(t, &h, PlayerThree, false, 22)
+	per = perspective(t, &h, PlayerThree)
+
+	if len(per.ThisTrick) != 0 || per.Leader != 0 || per.Trick != 2 {
+		t.Errorf("expected the table to be cleared for trick 2, but received %+v", per)
+	}
+}
+
+func TestFromSeats(t *testing.T) {
+	h := New()
+	h.Setup()
+
+	for _, seat := range []int{Nobody, 4} {
+		if _, err := h.From(seat); err == nil {
+			t.Errorf("expected an error for seat %d", seat)
+		}
+	}
+}
+
+func TestFromScores(t *testing.T) {
+	h := New(Options{Seed: 3})
+	h.Setup()
+	playRounds(t, &h, 1)
+
+	// play into the round until a few tricks have been taken and the next one is led
+	for len(h.Tricks()) < 6 || len(h.thisTrick()) == 0 {
+		p := h.PlayersTurn()[0]
+		cards := h.LegalMoves(p)[:1]
+
+		if h.Phase() == PhasePass {
+			cards = h.LegalMoves(p)[:3]
+		}
+
+		if err := h.Play(p, cards...); err != nil {
+			t.Fatalf("expected no error but received: %s", err)
+		}
+	}
+
+	per := perspective(t, &h, PlayerTwo)
+	tricks := h.Tricks()
+	taken := [4]int{}
+
+	for _, trick := range tricks {
+		taken[trick.Winner] += trick.Points
+	}
+
+	for p := range h.Players {
+		if per.HandSizes[p] != len(h.Players[p].Hand) {
+			t.Errorf("expected player %d to hold %d cards, but received %d", p+1, len(h.Players[p].Hand), per.HandSizes[p])
+		}
+
+		if per.RoundPoints[p] != taken[p] {
+			t.Errorf("expected player %d to have taken %d points, but received %d", p+1, taken[p], per.RoundPoints[p])
+		}
+
+		if per.PointsToLose[p] != h.Score()[p] {
+			t.Errorf("expected player %d to be %d points from losing, but received %d", p+1, h.Score()[p], per.PointsToLose[p])
+		}
+	}
+
+	if last := tricks[len(tricks)-1]; per.LastTrickPoints != last.Points {
+		t.Errorf("expected the last trick to hold %d points, but received %d", last.Points, per.LastTrickPoints)
+	}
+
+	// someone has led the trick, so they hold a card fewer than the player after them
+	if per.HandSizes[h.trickLeader()] != per.HandSizes[h.PlayersTurn()[0]]-1 {
+		t.Errorf("expected the leader to hold a card fewer than the player whose turn it is, but received %v", per.HandSizes)
+	}
+}
+
+func TestFromPasses(t *testing.T) {
+	h := New(Options{Seed: 5})
+	h.Setup()
+
+	passes := [4][]Card{}
+
+	for p := range passes {
+		passes[p] = append([]Card{}, h.LegalMoves(p)[:3]...)
+	}
+
+	if err := h.Play(PlayerOne, passes[PlayerOne]...); err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	// in the first round, player 1 passes to player 4 and is passed to by player 2
+	per := perspective(t, &h, PlayerOne)
+
+	if !reflect.DeepEqual(per.Passed, cardsToJSONCards(passes[PlayerOne]...)) || per.PassedTo != 4 || per.ReceivedFrom != 2 {
+		t.Errorf("expected player 1 to pass %v to player 4, but received %+v", passes[PlayerOne], per)
+	}
+
+	if len(per.Received) != 0 {
+		t.Errorf("expected no cards to be received before everyone has passed, but received %v", per.Received)
+	}
+
+	for p := PlayerTwo; p <= PlayerFour; p++ {
+		if err := h.Play(p, passes[p]...); err != nil {
+			t.Fatalf("expected no error but received: %s", err)
+		}
+	}
+
+	// the passes are still shown once play has started
+	p := h.PlayersTurn()[0]
+
+	if err := h.Play(p, h.LegalMoves(p)[0]); err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	per = perspective(t, &h, PlayerOne)
+
+	if !reflect.DeepEqual(per.Received, cardsToJSONCards(passes[PlayerTwo]...)) {
+		t.Errorf("expected player 1 to have received %v, but received %v", passes[PlayerTwo], per.Received)
+	}
+
+	if !reflect.DeepEqual(per.Passed, cardsToJSONCards(passes[PlayerOne]...)) {
+		t.Errorf("expected player 1 to have passed %v, but received %v", passes[PlayerOne], per.Passed)
+	}
+
+	// a new round starts with nothing passed, and passes the other way
+	playRounds(t, &h, 1)
+	per = perspective(t, &h, PlayerOne)
+
+	if len(per.Passed) != 0 || len(per.Received) != 0 || per.PassedTo != 2 || per.ReceivedFrom != 4 {
+		t.Errorf("expected nothing passed yet in round 2, passing to player 2, but received %+v", per)
+	}
+
+	// nobody is passed to when cards are held
+	playRounds(t, &h, 2)
+
+	if per = perspective(t, &h, PlayerOne); per.PassTo != "hold" || per.PassedTo != 0 || per.ReceivedFrom != 0 {
+		t.Errorf("expected no one to pass in round 4, but received %+v", per)
+	}
+}
+
+func perspective(t *testing.T, h *Hearts, player int) Perspective {
+	t.Helper()
+
+	var per Perspective
+	b, err := h.From(player)
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	if err := json.Unmarshal(b, &per); err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	return per
+}
@@ -0,0 +1,440 @@
+// Package lobby is where games of Hearts are put together. Players create tables, take
+// seats at them and mark themselves ready, and once all four seats are filled and everyone
+// is ready, the table's game is set up and handed to a Host to be played.
+//
+// Tables are either public, and listed by Tables, or private, and only joined with the
+// invite code handed out when they were created. A table can also fill its empty seats
+// with bots if it has waited too long for people to sit down. Tables that nobody is doing
+// anything at are removed after a while, whether or not their game has started.
+package lobby
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nolwn/go-hearts/bot"
+	"github.com/nolwn/go-hearts/hearts"
+)
+
+var (
+	// ErrBadInviteCode is returned when someone tries to sit at a private table without
+	// its invite code.
+	ErrBadInviteCode = errors.New("the invite code doesn't match the table's")
+
+	// ErrBadSeat is returned when a seat isn't one of the four at the table.
+	ErrBadSeat = errors.New("seats are numbered 0 through 3")
+
+	// ErrNoUser is returned when someone tries to sit down without saying who they are.
+	ErrNoUser = errors.New("a seat can only be taken by a user")
+
+	// ErrBadToken is returned when a token doesn't belong to anyone sitting at the table.
+	ErrBadToken = errors.New("nobody at the table has that token")
+
+	// ErrSeatTaken is returned when someone tries to sit in a seat that isn't empty.
+	ErrSeatTaken = errors.New("the seat has already been taken")
+
+	// ErrStarted is returned when the table's game has already started, so its seats can't
+	// change any more.
+	ErrStarted = errors.New("the table's game has already started")
+
+	// ErrTableNotFound is returned when there is no table with the given id.
+	ErrTableNotFound = errors.New("table not found")
+)
+
+// Host hosts the games started at a lobby's tables. A server.Server is a Host.
+type Host interface {
+
+	// Host starts hosting a game that has been set up, and returns the game's id. Each
+	// seat has its token, and the bots, keyed by player index, play the seats they are in.
+	Host(game *hearts.Hearts, tokens [4]string, bots map[int]bot.Player) (string, error)
+}
+
+// defaultKeepStarted is how long a table is kept after its game starts, unless the
+// Options say otherwise.
+const defaultKeepStarted = 10 * time.Minute
+
+// defaultKeepIdle is how long a table whose game hasn't started is kept once nobody is
+// doing anything at it, unless the Options say otherwise.
+const defaultKeepIdle = 30 * time.Minute
+
+// Options changes how a Lobby runs its tables.
+type Options struct {
+
+	// NewBot returns a bot to sit in an empty seat. By default, seats are filled with
+	// Heuristic bots.
+	NewBot func() bot.Player
+
+	// KeepStarted is how long a table is kept once its game has started, so that everyone
+	// who sat at it has time to look up the game's id. After that, the table is removed
+	// from the lobby. It defaults to ten minutes.
+	KeepStarted time.Duration
+
+	// KeepIdle is how long a table whose game hasn't started is kept after anyone last sat
+	// down, stood up or marked themselves ready at it. After that, the table is removed
+	// from the lobby. It defaults to thirty minutes.
+	KeepIdle time.Duration
+}
+
+// Lobby keeps track of the tables that are waiting for players. It is safe for concurrent
+// use.
+type Lobby struct {
+	mu          sync.Mutex
+	host        Host
+	keepIdle    time.Duration
+	keepStarted time.Duration
+	newBot      func() bot.Player
+	tables      map[string]*table
+}
+
+// New creates a Lobby that hands the games started at its tables to the host.
+func New(host Host, options ...Options) *Lobby {
+	l := &Lobby{
+		host:        host,
+		keepIdle:    defaultKeepIdle,
+		keepStarted: defaultKeepStarted,
+		newBot:      func() bot.Player { return bot.NewHeuristic() },
+		tables:      map[string]*table{},
+	}
+
+	for _, opt := range options {
+		if opt.NewBot != nil {
+			l.newBot = opt.NewBot
+		}
+
+		if opt.KeepStarted > 0 {
+			l.keepStarted = opt.KeepStarted
+		}
+
+		if opt.KeepIdle > 0 {
+			l.keepIdle = opt.KeepIdle
+		}
+	}
+
+	return l
+}
+
+// Create opens a new table. The Table returned is the only one with the table's
+// InviteCode, which has to be handed to anyone who is meant to sit at a private table.
+// Only the first TableOptions is used.
+func (l *Lobby) Create(options ...TableOptions) (Table, error) {
+	var opts TableOptions
+
+	if len(options) > 0 {
+		opts = options[0]
+	}
+
+	id, err := newToken(8)
+
+	if err != nil {
+		return Table{}, err
+	}
+
+	t := &table{
+		Table:     Table{ID: id, Private: opts.Private, Rules: opts.Rules},
+		active:    time.Now(),
+		botsAfter: opts.BotsAfter,
+	}
+
+	if opts.Private {
+		if t.InviteCode, err = newToken(4); err != nil {
+			return Table{}, err
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tables[id] = t
+	t.idle = time.AfterFunc(l.keepIdle, func() { l.expireIdle(id) })
+
+	l.waitForBots(t)
+
+	return t.Table, nil
+}
+
+// Table returns the table with the given id. Once the table's game has started, its GameID
+// is set, and the table can be found for as long as the Options' KeepStarted.
+func (l *Lobby) Table(id string) (Table, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	t, ok := l.tables[id]
+
+	if !ok {
+		return Table{}, ErrTableNotFound
+	}
+
+	return t.view(), nil
+}
+
+// Tables returns the public tables that are still waiting for players, ordered by id.
+func (l *Lobby) Tables() []Table {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	tables := []Table{}
+
+	for _, t := range l.tables {
+		if !t.Private && t.GameID == "" && !t.starting {
+			tables = append(tables, t.view())
+		}
+	}
+
+	sort.Slice(tables, func(i, j int) bool { return tables[i].ID < tables[j].ID })
+
+	return tables
+}
+
+// Sit takes the seat at the table for the user, and returns the seat's token. The token
+// proves the user is sitting there: it is needed to stand up or mark the seat ready, and
+// it is the token for the seat in the table's game once it starts. The invite code is only
+// checked at private tables.
+func (l *Lobby) Sit(id string, seat int, user string, inviteCode string) (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	t, err := l.waiting(id)
+
+	if err != nil {
+		return "", err
+	}
+
+	if t.Private && subtle.ConstantTimeCompare([]byte(inviteCode), []byte(t.InviteCode)) != 1 {
+		return "", ErrBadInviteCode
+	}
+
+	if seat < hearts.PlayerOne || seat > hearts.PlayerFour {
+		return "", ErrBadSeat
+	}
+
+	if user == "" {
+		return "", ErrNoUser
+	}
+
+	if !t.Seats[seat].empty() {
+		return "", ErrSeatTaken
+	}
+
+	token, err := newToken(16)
+
+	if err != nil {
+		return "", err
+	}
+
+	t.Seats[seat] = Seat{User: user}
+	t.tokens[seat] = token
+	t.active = time.Now()
+
+	if t.timer == nil {
+		l.waitForBots(t) // nobody was sitting here when the bots were last due
+	}
+
+	return token, nil
+}
+
+// Stand frees the seat with the token.
+func (l *Lobby) Stand(id string, token string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	t, err := l.waiting(id)
+
+	if err != nil {
+		return err
+	}
+
+	seat := t.seatWith(token)
+
+	if seat == hearts.Nobody {
+		return ErrBadToken
+	}
+
+	t.Seats[seat] = Seat{}
+	t.tokens[seat] = ""
+	t.active = time.Now()
+
+	return nil
+}
+
+// Ready marks the seat with the token as ready to play, or not. If every seat is filled
+// and ready afterward, the table's game starts.
+func (l *Lobby) Ready(id string, token string, ready bool) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	t, err := l.waiting(id)
+
+	if err != nil {
+		return err
+	}
+
+	seat := t.seatWith(token)
+
+	if seat == hearts.Nobody {
+		return ErrBadToken
+	}
+
+	t.Seats[seat].Ready = ready
+	t.active = time.Now()
+
+	return l.startIfReady(t)
+}
+
+// waitForBots starts the table's timer for filling it with bots, if it was asked to be.
+// It must be called with the lobby locked.
+func (l *Lobby) waitForBots(t *table) {
+	if t.botsAfter > 0 {
+		id := t.ID
+		t.timer = time.AfterFunc(t.botsAfter, func() { l.fillWithBots(id) })
+	}
+}
+
+// fillWithBots sits bots in the table's empty seats, as long as someone is sitting at it,
+// and starts the game if everyone else is ready. If nobody is, the table waits for bots
+// again once someone sits down.
+func (l *Lobby) fillWithBots(id string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	t, err := l.waiting(id)
+
+	if err != nil {
+		return
+	}
+
+	if t.seated() == 0 {
+		t.timer = nil
+		return
+	}
+
+	for seat := range t.Seats {
+		if t.Seats[seat].empty() {
+			t.Seats[seat] = Seat{Bot: true, Ready: true}
+		}
+	}
+
+	// there is nobody to tell if the game can't be started; the table stays open, and
+	// the next player to mark themselves ready tries again
+	l.startIfReady(t)
+}
+
+// startIfReady sets up the table's game and hands it to the host, if every seat is filled
+// and ready. It must be called with the lobby locked. The lock is let go while the host
+// starts the game, so that a slow host doesn't hold up every other table; the table is
+// marked as starting in the meantime, so that its seats can't change.
+func (l *Lobby) startIfReady(t *table) error {
+	for _, s := range t.Seats {
+		if s.empty() || !s.Ready {
+			return nil
+		}
+	}
+
+	game := hearts.New(hearts.Options{Rules: t.Rules})
+
+	if err := game.Setup(); err != nil {
+		return err
+	}
+
+	bots := map[int]bot.Player{}
+
+	for seat, s := range t.Seats {
+		if s.Bot {
+			bots[seat] = l.newBot()
+		}
+	}
+
+	t.starting = true
+	tokens := t.tokens
+
+	l.mu.Unlock()
+	gameID, err := l.host.Host(&game, tokens, bots)
+	l.mu.Lock()
+
+	t.starting = false
+
+	if err != nil {
+		return err
+	}
+
+	t.GameID = gameID
+	t.idle.Stop()
+
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+
+	t.timer = time.AfterFunc(l.keepStarted, func() { l.remove(t.ID) })
+
+	return nil
+}
+
+// remove takes the table out of the lobby.
+func (l *Lobby) remove(id string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.tables, id)
+}
+
+// expireIdle removes the table from the lobby if its game hasn't started and nobody has
+// done anything at it for the Options' KeepIdle. Otherwise, it checks again once the table
+// could have been idle for that long.
+func (l *Lobby) expireIdle(id string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	t, ok := l.tables[id]
+
+	if !ok || t.GameID != "" {
+		return // started tables are removed once everyone has had time to find the game
+	}
+
+	wait := l.keepIdle - time.Since(t.active)
+
+	if t.starting {
+		wait = l.keepIdle // the game may yet fail to start, leaving the table open
+	}
+
+	if wait > 0 {
+		t.idle = time.AfterFunc(wait, func() { l.expireIdle(id) })
+		return
+	}
+
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+
+	delete(l.tables, id)
+}
+
+// waiting returns the table with the given id, as long as its game hasn't started, or
+// isn't being started.
+func (l *Lobby) waiting(id string) (*table, error) {
+	t, ok := l.tables[id]
+
+	if !ok {
+		return nil, ErrTableNotFound
+	}
+
+	if t.GameID != "" || t.starting {
+		return nil, ErrStarted
+	}
+
+	return t, nil
+}
+
+// newToken returns a random hex string made from n bytes, which can't be guessed.
+func newToken(n int) (string, error) {
+	b := make([]byte, n)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
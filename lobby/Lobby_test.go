@@ -0,0 +1,320 @@
+package lobby
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/nolwn/go-hearts/bot"
+	"github.com/nolwn/go-hearts/hearts"
+	"github.com/nolwn/go-hearts/server"
+	"github.com/nolwn/go-hearts/store"
+)
+
+func TestLobbyStartsGame(t *testing.T) {
+	games := store.NewMemoryStore()
+	s := server.New(games)
+	l := New(s)
+
+	table, err := l.Create(TableOptions{Rules: hearts.Rules{TargetScore: 50}})
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	if tables := l.Tables(); len(tables) != 1 || tables[0].ID != table.ID {
+		t.Errorf("expected the table to be listed, but received %+v", tables)
+	}
+
+	tokens := [4]string{}
+
+	for seat := range tokens {
+		tokens[seat] = sit(t, l, table.ID, seat, "")
+	}
+
+	checkError(t, ErrSeatTaken)(l.Sit(table.ID, hearts.PlayerOne, "late", ""))
+
+	// the game doesn't start until everyone is ready
+	for seat := 0; seat < 3; seat++ {
+		checkNoError(t, l.Ready(table.ID, tokens[seat], true))
+	}
+
+	checkNoError(t, l.Ready(table.ID, tokens[0], false))
+	checkNoError(t, l.Ready(table.ID, tokens[3], true))
+
+	if table, _ = l.Table(table.ID); table.GameID != "" {
+		t.Fatal("expected the game to wait for player 1 to be ready again")
+	}
+
+	checkNoError(t, l.Ready(table.ID, tokens[0], true))
+	table, _ = l.Table(table.ID)
+
+	if table.GameID == "" {
+		t.Fatal("expected the game to start once everyone was ready")
+	}
+
+	if tables := l.Tables(); len(tables) != 0 {
+		t.Errorf("expected a table that has started not to be listed, but received %+v", tables)
+	}
+
+	if err := l.Stand(table.ID, tokens[0]); err != ErrStarted {
+		t.Errorf("expected %q but received %v", ErrStarted, err)
+	}
+
+	record, err := games.Load(table.GameID)
+
+	if err != nil {
+		t.Fatalf("expected the game to be hosted but received: %s", err)
+	}
+
+	if record.Game.Phase() != hearts.PhasePass || record.Game.Rules().TargetScore != 50 {
+		t.Errorf("expected a game to 50 that had been set up")
+	}
+
+	// the tokens handed out at the table are the seats' tokens in the game
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	for seat, token := range tokens {
+		res, err := http.Get(ts.URL + "/games/" + table.GameID + "/players/" + strconv.Itoa(seat+1) + "?token=" + token)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res.Body.Close()
+
+		if res.StatusCode != http.StatusOK {
+			t.Errorf("expected player %d's token to work, but received status %d", seat+1, res.StatusCode)
+		}
+	}
+}
+
+func TestLobbyPrivateTable(t *testing.T) {
+	l := New(server.New(store.NewMemoryStore()))
+	table, err := l.Create(TableOptions{Private: true})
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	if table.InviteCode == "" {
+		t.Fatal("expected a private table to have an invite code")
+	}
+
+	if tables := l.Tables(); len(tables) != 0 {
+		t.Errorf("expected a private table not to be listed, but received %+v", tables)
+	}
+
+	if found, _ := l.Table(table.ID); found.InviteCode != "" {
+		t.Error("expected the invite code to be kept secret")
+	}
+
+	checkError(t, ErrBadInviteCode)(l.Sit(table.ID, hearts.PlayerOne, "guest", ""))
+	checkError(t, ErrBadInviteCode)(l.Sit(table.ID, hearts.PlayerOne, "guest", "guess"))
+	token := sit(t, l, table.ID, hearts.PlayerOne, table.InviteCode)
+
+	// a seat that is given up can be taken by someone else
+	checkNoError(t, l.Stand(table.ID, token))
+	checkError(t, ErrBadToken)("", l.Ready(table.ID, token, true))
+	sit(t, l, table.ID, hearts.PlayerOne, table.InviteCode)
+}
+
+func TestLobbyErrors(t *testing.T) {
+	l := New(server.New(store.NewMemoryStore()))
+	table, _ := l.Create()
+
+	checkError(t, ErrTableNotFound)(l.Sit("nope", hearts.PlayerOne, "guest", ""))
+	checkError(t, ErrBadSeat)(l.Sit(table.ID, 4, "guest", ""))
+	checkError(t, ErrNoUser)(l.Sit(table.ID, hearts.PlayerOne, "", ""))
+	checkError(t, ErrBadToken)("", l.Stand(table.ID, ""))
+
+	if _, err := l.Table("nope"); err != ErrTableNotFound {
+		t.Errorf("expected %q but received %v", ErrTableNotFound, err)
+	}
+}
+
+func TestLobbyBots(t *testing.T) {
+	games := store.NewMemoryStore()
+	l := New(server.New(games), Options{NewBot: func() bot.Player { return bot.NewHeuristic() }})
+
+	empty, _ := l.Create(TableOptions{BotsAfter: time.Millisecond})
+	table, _ := l.Create(TableOptions{BotsAfter: 10 * time.Millisecond})
+	token := sit(t, l, table.ID, hearts.PlayerTwo, "")
+	checkNoError(t, l.Ready(table.ID, token, true))
+
+	deadline := time.Now().Add(2 * time.Second)
+
+	for table.GameID == "" && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+		table, _ = l.Table(table.ID)
+	}
+
+	if table.GameID == "" {
+		t.Fatal("expected bots to fill the table and start the game")
+	}
+
+	for seat, s := range table.Seats {
+		if s.Bot != (seat != hearts.PlayerTwo) {
+			t.Errorf("expected bots in every seat but player 2's, but seat %d is %+v", seat, s)
+		}
+	}
+
+	// the bots have passed already, so it is only the person's turn
+	record, err := games.Load(table.GameID)
+
+	if err != nil {
+		t.Fatalf("expected the game to be hosted but received: %s", err)
+	}
+
+	if turn := record.Game.PlayersTurn(); len(turn) != 1 || turn[0] != hearts.PlayerTwo {
+		t.Errorf("expected it to be player 2's turn alone, but it is %v's", turn)
+	}
+
+	// nobody sat at the other table, so no bots were sent to it
+	if empty, _ = l.Table(empty.ID); empty.GameID != "" || !empty.Seats[0].empty() {
+		t.Errorf("expected the empty table to be left alone, but it is %+v", empty)
+	}
+}
+
+func TestLobbyBotsAfterNobodySat(t *testing.T) {
+	games := store.NewMemoryStore()
+	l := New(server.New(games))
+	table, _ := l.Create(TableOptions{BotsAfter: time.Millisecond})
+
+	// the bots were due before anybody sat down, so they come once someone does
+	time.Sleep(20 * time.Millisecond)
+	token := sit(t, l, table.ID, hearts.PlayerOne, "")
+	checkNoError(t, l.Ready(table.ID, token, true))
+
+	deadline := time.Now().Add(2 * time.Second)
+
+	for table.GameID == "" && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+		table, _ = l.Table(table.ID)
+	}
+
+	if table.GameID == "" {
+		t.Fatal("expected bots to fill the table and start the game")
+	}
+
+	if _, err := games.Load(table.GameID); err != nil {
+		t.Errorf("expected the game to be hosted but received: %s", err)
+	}
+}
+
+// lookingHost is a Host that looks at the lobby while it starts a game.
+type lookingHost struct {
+	lobby *Lobby
+	id    string
+	err   error
+}
+
+func (h *lookingHost) Host(game *hearts.Hearts, tokens [4]string, bots map[int]bot.Player) (string, error) {
+	_, h.err = h.lobby.Sit(h.id, hearts.PlayerOne, "late", "")
+
+	return "game", nil
+}
+
+func TestLobbyStartingTable(t *testing.T) {
+	host := &lookingHost{}
+	l := New(host, Options{KeepStarted: 10 * time.Millisecond})
+	table, _ := l.Create()
+	host.lobby, host.id = l, table.ID
+
+	for seat := 0; seat < 4; seat++ {
+		checkNoError(t, l.Ready(table.ID, sit(t, l, table.ID, seat, ""), true))
+	}
+
+	// the lobby isn't locked while the game is started, but the seats can't change
+	if host.err != ErrStarted {
+		t.Errorf("expected %q but received %v", ErrStarted, host.err)
+	}
+
+	if table, _ = l.Table(table.ID); table.GameID != "game" {
+		t.Fatalf("expected the game to have started, but the table is %+v", table)
+	}
+
+	// the table is removed once everyone has had time to find the game
+	deadline := time.Now().Add(2 * time.Second)
+	_, err := l.Table(table.ID)
+
+	for err == nil && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+		_, err = l.Table(table.ID)
+	}
+
+	if err != ErrTableNotFound {
+		t.Errorf("expected %q but received %v", ErrTableNotFound, err)
+	}
+}
+
+func TestLobbyIdleTable(t *testing.T) {
+	l := New(server.New(store.NewMemoryStore()), Options{KeepIdle: 500 * time.Millisecond})
+	table, _ := l.Create()
+
+	// the table is kept for as long as people keep sitting down and standing up at it,
+	// well past the time it would be kept if nobody did
+	for i := 0; i < 20; i++ {
+		time.Sleep(40 * time.Millisecond)
+		checkNoError(t, l.Stand(table.ID, sit(t, l, table.ID, hearts.PlayerOne, "")))
+	}
+
+	if _, err := l.Table(table.ID); err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	// but once nobody does anything at it, it is removed before its game starts
+	deadline := time.Now().Add(2 * time.Second)
+	_, err := l.Table(table.ID)
+
+	for err == nil && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+		_, err = l.Table(table.ID)
+	}
+
+	if err != ErrTableNotFound {
+		t.Errorf("expected %q but received %v", ErrTableNotFound, err)
+	}
+}
+
+// sit sits a user in the seat, and returns the seat's token.
+func sit(t *testing.T, l *Lobby, id string, seat int, inviteCode string) string {
+	t.Helper()
+	token, err := l.Sit(id, seat, "user "+strconv.Itoa(seat), inviteCode)
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	if token == "" {
+		t.Fatal("expected a token for the seat")
+	}
+
+	return token
+}
+
+// checkError returns a function that checks that the error returned along with a token
+// is the expected one.
+func checkError(t *testing.T, expected error) func(string, error) {
+	t.Helper()
+
+	return func(_ string, err error) {
+		t.Helper()
+
+		if !errors.Is(err, expected) {
+			t.Errorf("expected %q but received %v", expected, err)
+		}
+	}
+}
+
+func checkNoError(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Errorf("expected no error but received: %s", err)
+	}
+}
@@ -0,0 +1,124 @@
+package lobby
+
+import (
+	"crypto/subtle"
+	"time"
+
+	"github.com/nolwn/go-hearts/hearts"
+)
+
+// TableOptions changes how a table is set up.
+type TableOptions struct {
+
+	// Private tables aren't listed by Tables, and can only be sat at with their invite
+	// code.
+	Private bool
+
+	// Rules are the house rules the table's game is played with.
+	Rules hearts.Rules
+
+	// BotsAfter is how long the table waits for people before sitting bots in its empty
+	// seats. Bots only join a table that someone is sitting at; if nobody is when the time
+	// is up, the table waits BotsAfter again from when the next person sits down. If it is
+	// zero, the table waits for people for as long as it takes.
+	BotsAfter time.Duration
+}
+
+// Table is a table in the lobby, as anyone can see it.
+type Table struct {
+
+	// ID identifies the table in the lobby.
+	ID string `json:"id"`
+
+	// GameID is the id of the table's game with the Host, once it has started.
+	GameID string `json:"gameId,omitempty"`
+
+	// InviteCode is needed to sit at a private table. It is only set in the Table returned
+	// when the table is created.
+	InviteCode string `json:"inviteCode,omitempty"`
+
+	// Private is true if the table can only be sat at with its invite code.
+	Private bool `json:"private"`
+
+	// Rules are the house rules the table's game is played with.
+	Rules hearts.Rules `json:"rules"`
+
+	// Seats are the table's seats, by player index.
+	Seats [4]Seat `json:"seats"`
+}
+
+// Seat is one of the seats at a table.
+type Seat struct {
+
+	// Bot is true if a bot has been sat in the seat.
+	Bot bool `json:"bot,omitempty"`
+
+	// Ready is true once whoever is sitting in the seat is ready to play.
+	Ready bool `json:"ready"`
+
+	// User is the user sitting in the seat, or empty if nobody is.
+	User string `json:"user,omitempty"`
+}
+
+// empty returns true if nobody, person or bot, is sitting in the seat.
+func (s Seat) empty() bool {
+	return s.User == "" && !s.Bot
+}
+
+// table is a table along with what only the lobby knows about it.
+type table struct {
+	Table
+
+	// tokens are the tokens of the people sitting at the table, by seat.
+	tokens [4]string
+
+	// starting is true while the table's game is being handed to the host.
+	starting bool
+
+	// botsAfter is how long the table waits for people before sitting bots with them.
+	botsAfter time.Duration
+
+	// timer fills the table with bots, if it was asked to. It is nil while the table waits
+	// for someone to sit down before waiting for bots again. Once the game has started, it
+	// removes the table from the lobby instead.
+	timer *time.Timer
+
+	// active is when someone last sat down, stood up or marked themselves ready.
+	active time.Time
+
+	// idle removes the table from the lobby if nobody has done anything at it for too
+	// long before its game starts.
+	idle *time.Timer
+}
+
+// seatWith returns the seat with the token, or Nobody if there isn't one.
+func (t *table) seatWith(token string) int {
+	for seat, seatToken := range t.tokens {
+		if token != "" && subtle.ConstantTimeCompare([]byte(seatToken), []byte(token)) == 1 {
+			return seat
+		}
+	}
+
+	return hearts.Nobody
+}
+
+// seated returns the number of people sitting at the table.
+func (t *table) seated() int {
+	n := 0
+
+	for _, s := range t.Seats {
+		if s.User != "" {
+			n++
+		}
+	}
+
+	return n
+}
+
+// view returns the table as anyone can see it.
+func (t *table) view() Table {
+	view := t.Table
+	view.InviteCode = ""
+
+	return view
+}
@@ -0,0 +1,213 @@
+package server
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/nolwn/go-hearts/bot"
+	"github.com/nolwn/go-hearts/hearts"
+	"github.com/nolwn/go-hearts/store"
+)
+
+// bots keeps the bots sitting in each game. Bots remember what they have seen, so each
+// game's bots take their turns one request at a time.
+//
+// The kind of bot in each seat is kept with the game in the store. A game whose bots
+// aren't kept here, such as one hosted before the server was restarted, has new bots of
+// the same kinds sat down the first time they are needed.
+type bots struct {
+	mu    sync.Mutex
+	games map[string]*botTable
+}
+
+// botTable is the bots sitting in one game, keyed by player index.
+type botTable struct {
+	mu      sync.Mutex
+	players map[int]bot.Player
+}
+
+// botError is returned when a move was made, but the bots couldn't take the turns that
+// follow it.
+type botError struct {
+	err error
+}
+
+func (e *botError) Error() string {
+	return "the bots couldn't take their turns: " + e.err.Error()
+}
+
+func newBots() *bots {
+	return &bots{games: map[string]*botTable{}}
+}
+
+// seat sits the bots at the game.
+func (b *bots) seat(id string, players map[int]bot.Player) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.games[id] = &botTable{players: players}
+}
+
+// add sits the bot in one seat of the game, alongside any bots already there.
+func (b *bots) add(record store.Record, player int, p bot.Player) error {
+	table, err := b.table(record)
+
+	if err != nil {
+		return err
+	}
+
+	table.mu.Lock()
+	table.players[player] = p
+	table.mu.Unlock()
+
+	return nil
+}
+
+// forget stands the game's bots up, so that new bots are sat down the next time they are
+// needed. Bots that took turns in a game that wasn't saved remember moves that were never
+// made, so they can't be trusted with the game that was.
+func (b *bots) forget(id string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.games, id)
+}
+
+// table returns the bots sitting in the game in the record. If there are none here, new
+// bots are sat down in the seats the record says have bots in them.
+func (b *bots) table(record store.Record) (*botTable, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if table := b.games[record.ID]; table != nil {
+		return table, nil
+	}
+
+	table := &botTable{players: map[int]bot.Player{}}
+
+	for i, seat := range record.Seats {
+		if seat.Bot == "" {
+			continue
+		}
+
+		p, err := bot.New(seat.Bot)
+
+		if err != nil {
+			return nil, fmt.Errorf("seat %d: %w", i+1, err)
+		}
+
+		table.players[i] = p
+	}
+
+	// games without bots aren't kept, so that only games with bots take up room
+	if len(table.players) > 0 {
+		b.games[record.ID] = table
+	}
+
+	return table, nil
+}
+
+// run has the bots in the record's game take their turns until it is a person's turn
+// again. It returns true if any of them had a turn to take.
+func (b *bots) run(record store.Record) (bool, error) {
+	table, err := b.table(record)
+
+	if err != nil {
+		return false, err
+	}
+
+	table.mu.Lock()
+	defer table.mu.Unlock()
+
+	if record.Game.Finished() {
+		return false, nil
+	}
+
+	for _, p := range record.Game.PlayersTurn() {
+		if table.players[p] != nil {
+			return true, bot.Run(record.Game, table.players)
+		}
+	}
+
+	return false, nil
+}
+
+// botKind returns the kind of bot the player is stored as. Bots from outside the bot
+// package can't be stored, so a Heuristic bot takes their place if they have to be sat
+// down again.
+func botKind(p bot.Player) string {
+	if kind := bot.Kind(p); kind != "" {
+		return kind
+	}
+
+	return bot.KindHeuristic
+}
+
+// Host starts hosting a game that has already been set up elsewhere, such as at a lobby
+// table, and returns its id. Each seat is given its token, and the bots, keyed by player
+// index, play the seats they are in. Seats with bots in them should have empty tokens so
+// that nobody else can play them. The bots take any turns they have straight away.
+//
+// Hosted games aren't given a kibitz token, so they can only be watched as a spectator.
+func (s *Server) Host(game *hearts.Hearts, tokens [4]string, players map[int]bot.Player) (string, error) {
+	if err := bot.Run(game, players); err != nil {
+		return "", err
+	}
+
+	seats := [4]store.Seat{}
+
+	for i, token := range tokens {
+		seats[i].Token = token
+	}
+
+	for i, p := range players {
+		seats[i].Bot = botKind(p)
+	}
+
+	record, err := s.games.Create(store.Record{Game: game, Seats: seats})
+
+	if err != nil {
+		return "", err
+	}
+
+	if len(players) > 0 {
+		s.bots.seat(record.ID, players)
+	}
+
+	return record.ID, nil
+}
+
+// runBots has the game's bots take any turns they have, and saves and publishes the game
+// if they took any. If a move is saved in between, new bots take their turns in the newer
+// game, since the ones that took turns in the game that wasn't saved remember them. It
+// returns the game as it is afterward.
+func (s *Server) runBots(id string) (store.Record, error) {
+	for {
+		record, err := s.games.Load(id)
+
+		if err != nil {
+			return record, err
+		}
+
+		acted, err := s.bots.run(record)
+
+		if err != nil || !acted {
+			return record, err
+		}
+
+		saved, err := s.games.Save(record)
+
+		if err == store.ErrConflict {
+			s.bots.forget(id)
+			continue
+		}
+
+		if err != nil {
+			return record, err
+		}
+
+		s.live.publish(id, saved.Version, saved.Game)
+
+		return saved, nil
+	}
+}
@@ -0,0 +1,187 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nolwn/go-hearts/bot"
+	"github.com/nolwn/go-hearts/hearts"
+	"github.com/nolwn/go-hearts/store"
+)
+
+func TestServerHost(t *testing.T) {
+	s := New(store.NewMemoryStore())
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	game := hearts.New(hearts.Options{Seed: 3})
+	game.Setup()
+
+	bots := map[int]bot.Player{
+		hearts.PlayerTwo:   bot.NewHeuristic(),
+		hearts.PlayerThree: bot.NewHeuristic(),
+		hearts.PlayerFour:  bot.NewHeuristic(),
+	}
+
+	id, err := s.Host(&game, [4]string{"player-one"}, bots)
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	created := createdBody{ID: id, Tokens: [4]string{"player-one"}}
+
+	// the bots pass as soon as the game is hosted
+	per := viewGame(t, ts, created, 1)
+
+	if len(per.HasPassed) != 3 {
+		t.Fatalf("expected the three bots to have passed, but %v have", per.HasPassed)
+	}
+
+	// and play once the person has passed, until it is the person's turn
+	checkStatus(t, pass(t, ts, created, 1, per.Hand[:3]...), http.StatusOK)
+	per = viewGame(t, ts, created, 1)
+
+	if per.Phase != "play" || per.Turn != 1 {
+		t.Errorf("expected it to be player 1's turn to play, but received %+v", per)
+	}
+
+	// nobody can play for the bots
+	res := getAs(t, ts, "/games/"+id+"/players/2", "")
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected status %d but received %d", http.StatusUnauthorized, res.StatusCode)
+	}
+}
+
+func TestServerHostRestart(t *testing.T) {
+	games := store.NewMemoryStore()
+	s := New(games)
+
+	game := hearts.New(hearts.Options{Seed: 3})
+	game.Setup()
+
+	bots := map[int]bot.Player{
+		hearts.PlayerTwo:   bot.NewHeuristic(),
+		hearts.PlayerThree: bot.NewMonteCarlo(bot.MonteCarloOptions{Iterations: 50}),
+		hearts.PlayerFour:  bot.NewHeuristic(),
+	}
+
+	id, err := s.Host(&game, [4]string{"player-one"}, bots)
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	// a new server on the same store has to sit the bots down again
+	ts := httptest.NewServer(New(games))
+	defer ts.Close()
+
+	created := createdBody{ID: id, Tokens: [4]string{"player-one"}}
+	per := viewGame(t, ts, created, 1)
+	checkStatus(t, pass(t, ts, created, 1, per.Hand[:3]...), http.StatusOK)
+	per = viewGame(t, ts, created, 1)
+
+	if per.Phase != "play" || per.Turn != 1 {
+		t.Errorf("expected it to be player 1's turn to play, but received %+v", per)
+	}
+}
+
+// conflictStore is a GameStore that turns down one Save as if another move had been saved
+// first.
+type conflictStore struct {
+	store.GameStore
+	saves      int
+	conflictAt int
+}
+
+func (s *conflictStore) Save(record store.Record) (store.Record, error) {
+	s.saves++
+
+	if s.saves == s.conflictAt {
+		return record, store.ErrConflict
+	}
+
+	return s.GameStore.Save(record)
+}
+
+func TestServerBotsConflict(t *testing.T) {
+	games := &conflictStore{GameStore: store.NewMemoryStore()}
+	s := New(games)
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	game := hearts.New(hearts.Options{Seed: 1})
+	game.Setup()
+
+	bots := map[int]bot.Player{
+		hearts.PlayerTwo:   bot.NewHeuristic(),
+		hearts.PlayerThree: bot.NewHeuristic(),
+		hearts.PlayerFour:  bot.NewHeuristic(),
+	}
+
+	id, err := s.Host(&game, [4]string{"player-one"}, bots)
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	// a bot leads in this deal, so the bots take turns once the person has passed. The
+	// pass is saved, but the bots' turns after it aren't
+	games.conflictAt = games.saves + 2
+	created := createdBody{ID: id, Tokens: [4]string{"player-one"}}
+	per := viewGame(t, ts, created, 1)
+	checkStatus(t, pass(t, ts, created, 1, per.Hand[:3]...), http.StatusOK)
+
+	if games.saves <= games.conflictAt {
+		t.Fatal("expected the bots to take their turns again after the conflict")
+	}
+
+	// the bots that saw the turns that weren't saved are replaced
+	table, _ := s.bots.table(store.Record{ID: id})
+
+	for seat, p := range bots {
+		if table.players[seat] == p {
+			t.Errorf("expected the bot in seat %d to be replaced after the conflict", seat+1)
+		}
+	}
+
+	if per = viewGame(t, ts, created, 1); per.Phase != "play" || per.Turn != 1 {
+		t.Errorf("expected it to be player 1's turn to play, but received %+v", per)
+	}
+}
+
+// failingBot is a bot that can't decide on anything.
+type failingBot struct{}
+
+func (failingBot) ChoosePass(per hearts.Perspective) ([]hearts.Card, error) {
+	return nil, errors.New("no idea")
+}
+
+func (failingBot) ChoosePlay(per hearts.Perspective) (hearts.Card, error) {
+	return hearts.Nobody, errors.New("no idea")
+}
+
+func TestServerBotFails(t *testing.T) {
+	s := New(store.NewMemoryStore())
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	created := create(t, ts, "")
+	hand := viewGame(t, ts, created, 1).Hand
+
+	if err := s.Revoke(created.ID, hearts.PlayerTwo, failingBot{}); err == nil {
+		t.Fatal("expected the bot's error")
+	}
+
+	// the person's move is kept, but the bot's failure isn't their fault
+	body := checkStatus(t, pass(t, ts, created, 1, hand[:3]...), http.StatusInternalServerError)
+	checkCode(t, body, "")
+
+	if per := viewGame(t, ts, created, 1); len(per.Hand) != 10 {
+		t.Errorf("expected player 1 to hold 10 cards after passing, but they hold %d", len(per.Hand))
+	}
+}
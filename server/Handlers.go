@@ -0,0 +1,371 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/nolwn/go-hearts/game"
+	"github.com/nolwn/go-hearts/hearts"
+	"github.com/nolwn/go-hearts/store"
+)
+
+var (
+	errBadKibitzToken = errors.New("the game's kibitz token is required")
+	errBadPlayer      = errors.New("players are numbered 1 through 4")
+	errBadToken       = errors.New("a valid token for the seat is required")
+)
+
+// createBody is the optional JSON body sent to create a game. Games created with the same
+// seed are dealt the same hands, which is handy for bug reports and tournaments. Ranked
+// games are shuffled with crypto/rand instead, so their seed doesn't give the deal away.
+// Rules left out of the body follow the standard game.
+type createBody struct {
+	Ranked bool         `json:"ranked"`
+	Rules  hearts.Rules `json:"rules"`
+	Seed   int64        `json:"seed"`
+}
+
+// createdBody is the JSON body returned when a game is created. Tokens are the tokens for
+// each seat, from player 1 to player 4, which prove a connection belongs to that seat.
+// Every token goes to whoever created the game, who is trusted to hand each one to the
+// person sitting in that seat; the server has no way of its own for joining a seat. The
+// lobby package seats people at a game without anyone holding a token but their own.
+// KibitzToken is only set if the rules allow kibitzing. It lets whoever holds it see every
+// hand, so it is meant for people watching the game, never for anyone playing it.
+type createdBody struct {
+	ID          string    `json:"id"`
+	Tokens      [4]string `json:"tokens"`
+	KibitzToken string    `json:"kibitzToken,omitempty"`
+}
+
+// moveBody is the JSON body a player sends to make a move. Type is either "pass", with
+// the three cards being passed in Cards, or "play", with the card being played in Card.
+type moveBody struct {
+	Type  string            `json:"type"`
+	Card  *hearts.JSONCard  `json:"card,omitempty"`
+	Cards []hearts.JSONCard `json:"cards,omitempty"`
+}
+
+// createGame starts a new game and deals the first round.
+func (s *Server) createGame(w http.ResponseWriter, r *http.Request) {
+	var body createBody
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && err != io.EOF {
+		writeError(w, http.StatusBadRequest, "unable to read game: "+err.Error())
+		return
+	}
+
+	opts := hearts.Options{Rules: body.Rules, Seed: body.Seed}
+
+	if body.Ranked {
+		opts.Shuffler = hearts.CryptoShuffler{}
+	}
+
+	game := hearts.New(opts)
+
+	if err := game.Setup(); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	seats, err := issueTokens()
+
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	record := store.Record{Game: &game, Seats: seats}
+
+	if game.Rules().KibitzDelay > 0 {
+		if record.KibitzToken, err = newToken(); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	record, err = s.games.Create(record)
+
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+
+	created := createdBody{ID: record.ID, KibitzToken: record.KibitzToken}
+
+	for i, seat := range seats {
+		created.Tokens[i] = seat.Token
+	}
+
+	w.Header().Set("Location", "/games/"+record.ID)
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// viewGame responds with the game as the given player sees it.
+func (s *Server) viewGame(w http.ResponseWriter, r *http.Request, id string, player int) {
+	record, err := s.games.Load(id)
+
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+
+	writePerspective(w, record.Game, player)
+}
+
+// spectateGame responds with the game as someone watching it sees it. Nobody's hand is
+// ever shown. If the game's rules let kibitzers see the hands, the game is shown as it
+// was as many tricks ago as they are.
+func (s *Server) spectateGame(w http.ResponseWriter, r *http.Request, id string) {
+	record, err := s.games.Load(id)
+
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+
+	b, err := record.Game.Spectate()
+
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(b)
+}
+
+// kibitzGame responds with the game as a kibitzer sees it: the same as a spectator, but
+// with every hand shown. The request has to carry the game's kibitz token, which is only
+// handed out to whoever created the game. Otherwise, the response is a 401 Unauthorized.
+func (s *Server) kibitzGame(w http.ResponseWriter, r *http.Request, id string) {
+	record, err := s.games.Load(id)
+
+	if err != nil && err != store.ErrNotFound {
+		writeStoreError(w, err)
+		return
+	}
+
+	if err != nil || !checkKibitzToken(record, seatToken(r)) {
+		writeError(w, http.StatusUnauthorized, errBadKibitzToken.Error())
+		return
+	}
+
+	b, err := record.Game.Kibitz()
+
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(b)
+}
+
+// reviewRound responds with a review of the last round of the game that was played to the
+// end. Every card in it has been played in the open, so anyone can see it. If no round has
+// been finished yet, the response is a 404 Not Found.
+func (s *Server) reviewRound(w http.ResponseWriter, r *http.Request, id string) {
+	record, err := s.games.Load(id)
+
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+
+	b, err := record.Game.Review()
+
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(b)
+}
+
+// scoreSheet responds with the game's score sheet, as CSV, with a row for every round that
+// has been played to the end.
+func (s *Server) scoreSheet(w http.ResponseWriter, r *http.Request, id string) {
+	record, err := s.games.Load(id)
+
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+
+	var b bytes.Buffer
+
+	if err := record.Game.ScoreSheet().WriteCSV(&b); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.WriteHeader(http.StatusOK)
+	w.Write(b.Bytes())
+}
+
+// playMove makes the move in the request body for the given player and responds with the
+// game as that player sees it afterward.
+//
+// Moves that can't be made are responded to by writeMoveError. If another move was saved
+// while this one was being played, nothing is saved and the response is a 409 Conflict.
+func (s *Server) playMove(w http.ResponseWriter, r *http.Request, id string, player int) {
+	var body moveBody
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "unable to read move: "+err.Error())
+		return
+	}
+
+	move, err := body.move(player)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	record, err := s.apply(id, move, seatToken(r))
+
+	if err != nil {
+		writeMoveError(w, err)
+		return
+	}
+
+	writePerspective(w, record.Game, player)
+}
+
+// apply makes the move in the stored game and saves it, then lets any bots in the game
+// take the turns that follow. The token has to be the token of the seat making the move
+// in the game that the move is made in, so that a seat revoked while the move was on its
+// way can't make it; if the seat is revoked after the game is loaded, saving the move
+// fails. Everyone watching the game live is sent the game after the move, and again after
+// the bots' moves. It returns the game as it is once the bots are done.
+//
+// The move is kept even if the bots fail to take their turns, in which case the error is
+// logged and a *botError is returned.
+func (s *Server) apply(id string, move game.Move, token string) (store.Record, error) {
+	record, err := s.games.Load(id)
+
+	if err != nil {
+		return record, err
+	}
+
+	if !checkToken(record, move.Player(), token) {
+		return record, errBadToken
+	}
+
+	if err := record.Game.Apply(move); err != nil {
+		return record, err
+	}
+
+	record, err = s.games.Save(record)
+
+	if err != nil {
+		return record, err
+	}
+
+	s.live.publish(id, record.Version, record.Game)
+
+	after, err := s.runBots(id)
+
+	if err != nil {
+		log.Printf("game %s: the bots couldn't take their turns: %s", id, err)
+		return record, &botError{err: err}
+	}
+
+	return after, nil
+}
+
+// move turns the body into the move that the player is making.
+func (b moveBody) move(player int) (game.Move, error) {
+	switch b.Type {
+	case "pass":
+		cards := make([]hearts.Card, 0, len(b.Cards))
+
+		for _, c := range b.Cards {
+			card, err := hearts.NewCard(c.Value, c.Suit)
+
+			if err != nil {
+				return nil, err
+			}
+
+			cards = append(cards, card)
+		}
+
+		return hearts.PassMove{Seat: player, Cards: cards}, nil
+
+	case "play":
+		if b.Card == nil {
+			return nil, errors.New("a play needs a card")
+		}
+
+		card, err := hearts.NewCard(b.Card.Value, b.Card.Suit)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return hearts.PlayMove{Seat: player, Card: card}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown move type %q", b.Type)
+	}
+}
+
+// writeMoveError responds to an error returned by apply. Moves that don't fit the state
+// of the game are a 409 Conflict; moves the rules don't allow are a 422 Unprocessable
+// Entity. Either way, the body carries the error's code. Bots that couldn't take their
+// turns after the move are a 500 Internal Server Error, since the move itself was made. A
+// seat that was revoked before the move was made is a 401 Unauthorized. Errors from the
+// store are responded to by writeStoreError.
+func writeMoveError(w http.ResponseWriter, err error) {
+	var be *botError
+	var re *hearts.RuleError
+
+	if err == errBadToken {
+		writeError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if errors.As(err, &be) {
+		writeError(w, http.StatusInternalServerError, be.Error())
+		return
+	}
+
+	if !errors.As(err, &re) {
+		writeStoreError(w, err)
+		return
+	}
+
+	status := http.StatusUnprocessableEntity
+
+	switch re.Code {
+	case hearts.CodeGameFinished, hearts.CodeNotYourTurn, hearts.CodeWrongPhase:
+		status = http.StatusConflict
+	}
+
+	writeJSON(w, status, errorBody{Error: re.Error(), Code: string(re.Code)})
+}
+
+func writePerspective(w http.ResponseWriter, game *hearts.Hearts, player int) {
+	b, err := game.From(player)
+
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(b)
+}
@@ -0,0 +1,316 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/nolwn/go-hearts/hearts"
+)
+
+const (
+
+	// liveBuffer is how many messages can wait to be sent to a connection. A connection
+	// that falls further behind than that is closed, and the client has to reconnect.
+	liveBuffer = 16
+
+	// pingPeriod is how often connections are pinged, and pongWait is how long a
+	// connection can go without answering before it is closed.
+	pingPeriod = 30 * time.Second
+	pongWait   = 60 * time.Second
+
+	writeWait = 10 * time.Second
+)
+
+// upgrader accepts WebSocket connections from any origin. Connections are tied to a seat
+// by its token, not by cookies, so other sites can't make use of a player's seat.
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// liveMessage is the JSON message sent over a live connection. Type is "perspective" when
+// the game has changed, with the seat's new Perspective and the Version of the game it was
+// taken from, or "error" when a move sent over the connection couldn't be made. Clients
+// should ignore any Perspective with a lower Version than one they've already seen.
+type liveMessage struct {
+	Type        string          `json:"type"`
+	Version     int             `json:"version,omitempty"`
+	Perspective json.RawMessage `json:"perspective,omitempty"`
+	Error       string          `json:"error,omitempty"`
+	Code        string          `json:"code,omitempty"`
+}
+
+// hub keeps track of the live connection for each seat of each game, so that they can be
+// sent the game whenever it changes. It also keeps track of everyone watching a game's
+// event stream, who are only told that the game has changed.
+type hub struct {
+	mu       sync.Mutex
+	games    map[string]map[int]*liveConn
+	watchers map[string]map[chan struct{}]bool
+}
+
+func newHub() *hub {
+	return &hub{
+		games:    map[string]map[int]*liveConn{},
+		watchers: map[string]map[chan struct{}]bool{},
+	}
+}
+
+// join makes the connection the seat's live connection. Each seat has at most one, so a
+// seat that reconnects closes the connection it had before.
+func (h *hub) join(id string, seat int, conn *liveConn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.games[id] == nil {
+		h.games[id] = map[int]*liveConn{}
+	}
+
+	if old := h.games[id][seat]; old != nil {
+		old.close()
+	}
+
+	h.games[id][seat] = conn
+}
+
+// leave removes the connection from the game, if it is still the seat's connection.
+func (h *hub) leave(id string, seat int, conn *liveConn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.games[id][seat] != conn {
+		return
+	}
+
+	delete(h.games[id], seat)
+
+	if len(h.games[id]) == 0 {
+		delete(h.games, id)
+	}
+}
+
+// kick closes the seat's live connection, if it has one.
+func (h *hub) kick(id string, seat int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if conn := h.games[id][seat]; conn != nil {
+		conn.close()
+		delete(h.games[id], seat)
+	}
+}
+
+// watch returns a channel that receives a value whenever the game changes. Changes that
+// happen before the last one has been received are folded into it, so a watcher has to
+// catch up on everything that changed, not just one move.
+func (h *hub) watch(id string) chan struct{} {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.watchers[id] == nil {
+		h.watchers[id] = map[chan struct{}]bool{}
+	}
+
+	changed := make(chan struct{}, 1)
+	h.watchers[id][changed] = true
+
+	return changed
+}
+
+// unwatch stops the channel from receiving changes to the game.
+func (h *hub) unwatch(id string, changed chan struct{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.watchers[id], changed)
+
+	if len(h.watchers[id]) == 0 {
+		delete(h.watchers, id)
+	}
+}
+
+// publish sends every seat connected to the game its Perspective of the game, and tells
+// everyone watching it that it has changed.
+func (h *hub) publish(id string, version int, game *hearts.Hearts) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for seat, conn := range h.games[id] {
+		conn.sendPerspective(version, game, seat)
+	}
+
+	for changed := range h.watchers[id] {
+		select {
+		case changed <- struct{}{}:
+		default: // the watcher hasn't caught up on the last change yet
+		}
+	}
+}
+
+// liveConn is a WebSocket connection for one seat. Messages are queued by send and
+// written by a goroutine of their own, so that a slow client never holds up a move.
+type liveConn struct {
+	ws   *websocket.Conn
+	out  chan []byte
+	done chan struct{}
+	once sync.Once
+}
+
+func newLiveConn(ws *websocket.Conn) *liveConn {
+	conn := &liveConn{
+		ws:   ws,
+		out:  make(chan []byte, liveBuffer),
+		done: make(chan struct{}),
+	}
+
+	go conn.write()
+
+	return conn
+}
+
+// close closes the connection. It is safe to call more than once.
+func (c *liveConn) close() {
+	c.once.Do(func() {
+		close(c.done)
+		c.ws.Close()
+	})
+}
+
+// send queues a message. If the client is too far behind, it is disconnected instead.
+func (c *liveConn) send(msg liveMessage) {
+	b, err := json.Marshal(msg)
+
+	if err != nil {
+		return
+	}
+
+	select {
+	case c.out <- b:
+	case <-c.done:
+	default:
+		c.close()
+	}
+}
+
+// sendPerspective queues the seat's Perspective of the game.
+func (c *liveConn) sendPerspective(version int, game *hearts.Hearts, seat int) {
+	b, err := game.From(seat)
+
+	if err != nil {
+		c.send(liveMessage{Type: "error", Error: err.Error()})
+		return
+	}
+
+	c.send(liveMessage{Type: "perspective", Version: version, Perspective: b})
+}
+
+// write sends queued messages, and pings the client so that dead connections are found.
+func (c *liveConn) write() {
+	ping := time.NewTicker(pingPeriod)
+	defer ping.Stop()
+	defer c.close()
+
+	for {
+		select {
+		case b := <-c.out:
+			c.ws.SetWriteDeadline(time.Now().Add(writeWait))
+
+			if err := c.ws.WriteMessage(websocket.TextMessage, b); err != nil {
+				return
+			}
+
+		case <-ping.C:
+			c.ws.SetWriteDeadline(time.Now().Add(writeWait))
+
+			if err := c.ws.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// liveGame upgrades the request to a WebSocket connection for the seat. The seat's token
+// must be given with the request, usually in the token query parameter. The connection is
+// sent the seat's Perspective straight away, and again whenever anyone makes a move.
+// Moves are sent over the connection in the same JSON as they are posted to the moves
+// endpoint.
+//
+// Reconnecting with the same token replaces the seat's earlier connection, and since each
+// connection starts with the current Perspective, nothing is lost in between. The
+// connection joins the game before the game is loaded for that first Perspective, so a
+// move made in between is sent as well.
+func (s *Server) liveGame(w http.ResponseWriter, r *http.Request, id string, player int) {
+	if !s.authorize(w, r, id, player) {
+		return
+	}
+
+	ws, err := upgrader.Upgrade(w, r, nil)
+
+	if err != nil {
+		return // the upgrader has already responded
+	}
+
+	conn := newLiveConn(ws)
+	s.live.join(id, player, conn)
+	defer s.live.leave(id, player, conn)
+	defer conn.close()
+
+	record, err := s.games.Load(id)
+
+	if err != nil {
+		conn.send(liveMessage{Type: "error", Error: err.Error()})
+		return
+	}
+
+	conn.sendPerspective(record.Version, record.Game, player)
+
+	ws.SetReadDeadline(time.Now().Add(pongWait))
+	ws.SetPongHandler(func(string) error {
+		return ws.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, b, err := ws.ReadMessage()
+
+		if err != nil { // the connection is closed or broken
+			return
+		}
+
+		var body moveBody
+
+		if err := json.Unmarshal(b, &body); err != nil {
+			conn.send(liveMessage{Type: "error", Error: "unable to read move: " + err.Error()})
+			continue
+		}
+
+		move, err := body.move(player)
+
+		if err != nil {
+			conn.send(liveMessage{Type: "error", Error: err.Error()})
+			continue
+		}
+
+		if _, err := s.apply(id, move, seatToken(r)); err != nil {
+			conn.send(errorMessage(err))
+		}
+	}
+}
+
+// errorMessage turns an error from apply into a message for a live connection.
+func errorMessage(err error) liveMessage {
+	msg := liveMessage{Type: "error", Error: err.Error()}
+
+	var re *hearts.RuleError
+
+	if errors.As(err, &re) {
+		msg.Code = string(re.Code)
+	}
+
+	return msg
+}
@@ -0,0 +1,206 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/nolwn/go-hearts/bot"
+	"github.com/nolwn/go-hearts/hearts"
+	"github.com/nolwn/go-hearts/store"
+)
+
+func TestLiveGame(t *testing.T) {
+	ts := httptest.NewServer(New(store.NewMemoryStore()))
+	defer ts.Close()
+
+	created := create(t, ts, "")
+	first := dialLive(t, ts, created, 1)
+	defer first.Close()
+	second := dialLive(t, ts, created, 2)
+	defer second.Close()
+
+	// each seat is sent its own hand as soon as it connects
+	hand := readPerspective(t, first).Hand
+	readPerspective(t, second)
+
+	if len(hand) != 13 {
+		t.Fatalf("expected player 1 to be sent 13 cards, but was sent %d", len(hand))
+	}
+
+	// a move over the connection is sent to every connected seat
+	sendMove(t, first, moveBody{Type: "pass", Cards: hand[:3]})
+
+	for _, conn := range []*websocket.Conn{first, second} {
+		if per := readPerspective(t, conn); len(per.HasPassed) != 1 || per.HasPassed[0] != 1 {
+			t.Errorf("expected player 1 to have passed, but %v have", per.HasPassed)
+		}
+	}
+
+	// and so is a move made over HTTP
+	third := viewGame(t, ts, created, 3).Hand
+	checkStatus(t, pass(t, ts, created, 3, third[:3]...), http.StatusOK)
+
+	for _, conn := range []*websocket.Conn{first, second} {
+		if per := readPerspective(t, conn); len(per.HasPassed) != 2 {
+			t.Errorf("expected two players to have passed, but %v have", per.HasPassed)
+		}
+	}
+}
+
+func TestLiveErrors(t *testing.T) {
+	ts := httptest.NewServer(New(store.NewMemoryStore()))
+	defer ts.Close()
+
+	created := create(t, ts, "")
+
+	// the wrong seat's token
+	_, res, err := websocket.DefaultDialer.Dial(liveURL(ts, created.ID, 1, created.Tokens[1]), nil)
+
+	if err == nil || res.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected a %d response, but received %v", http.StatusUnauthorized, err)
+	}
+
+	conn := dialLive(t, ts, created, 1)
+	defer conn.Close()
+
+	hand := readPerspective(t, conn).Hand
+
+	// a move that breaks the rules is answered with its code
+	sendMove(t, conn, moveBody{Type: "pass", Cards: hand[:2]})
+	checkLiveError(t, conn, hearts.CodeWrongPassCount)
+
+	// as is a move that isn't a move at all
+	if err := conn.WriteMessage(websocket.TextMessage, []byte("{")); err != nil {
+		t.Fatal(err)
+	}
+
+	checkLiveError(t, conn, "")
+
+	// the connection is still usable afterward
+	sendMove(t, conn, moveBody{Type: "pass", Cards: hand[:3]})
+	readPerspective(t, conn)
+}
+
+func TestLiveReconnect(t *testing.T) {
+	ts := httptest.NewServer(New(store.NewMemoryStore()))
+	defer ts.Close()
+
+	created := create(t, ts, "")
+	old := dialLive(t, ts, created, 1)
+	defer old.Close()
+	readPerspective(t, old)
+
+	conn := dialLive(t, ts, created, 1)
+	defer conn.Close()
+	hand := readPerspective(t, conn).Hand
+
+	// the seat's old connection is closed once it reconnects
+	old.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+	if _, _, err := old.ReadMessage(); err == nil {
+		t.Error("expected the old connection to be closed")
+	}
+
+	sendMove(t, conn, moveBody{Type: "pass", Cards: hand[:3]})
+
+	if per := readPerspective(t, conn); len(per.Hand) != 10 {
+		t.Errorf("expected player 1 to hold 10 cards after passing, but they hold %d", len(per.Hand))
+	}
+}
+
+func TestLiveRevoke(t *testing.T) {
+	s := New(store.NewMemoryStore())
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	created := create(t, ts, "")
+	conn := dialLive(t, ts, created, 1)
+	defer conn.Close()
+	readPerspective(t, conn)
+
+	// a seat handed over to a bot is disconnected, and can't connect again
+	if err := s.Revoke(created.ID, hearts.PlayerOne, bot.NewHeuristic()); err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+	if _, _, err := conn.ReadMessage(); err == nil {
+		t.Error("expected the connection to be closed")
+	}
+
+	_, res, err := websocket.DefaultDialer.Dial(liveURL(ts, created.ID, 1, created.Tokens[0]), nil)
+
+	if err == nil || res.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected a %d response, but received %v", http.StatusUnauthorized, err)
+	}
+}
+
+func checkLiveError(t *testing.T, conn *websocket.Conn, expected hearts.ErrorCode) {
+	t.Helper()
+	msg := readLive(t, conn)
+
+	if msg.Type != "error" || msg.Code != string(expected) {
+		t.Errorf("expected an error with code %q but received %+v", expected, msg)
+	}
+}
+
+func dialLive(t *testing.T, ts *httptest.Server, created createdBody, player int) *websocket.Conn {
+	t.Helper()
+	conn, _, err := websocket.DefaultDialer.Dial(liveURL(ts, created.ID, player, created.Tokens[player-1]), nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return conn
+}
+
+func liveURL(ts *httptest.Server, id string, player int, token string) string {
+	return "ws" + strings.TrimPrefix(ts.URL, "http") + "/games/" + id + "/players/" +
+		strconv.Itoa(player) + "/live?token=" + token
+}
+
+func readLive(t *testing.T, conn *websocket.Conn) liveMessage {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+	var msg liveMessage
+
+	if err := conn.ReadJSON(&msg); err != nil {
+		t.Fatal(err)
+	}
+
+	return msg
+}
+
+func readPerspective(t *testing.T, conn *websocket.Conn) hearts.Perspective {
+	t.Helper()
+	msg := readLive(t, conn)
+
+	if msg.Type != "perspective" {
+		t.Fatalf("expected a perspective but received %+v", msg)
+	}
+
+	var per hearts.Perspective
+
+	if err := json.Unmarshal(msg.Perspective, &per); err != nil {
+		t.Fatal(err)
+	}
+
+	return per
+}
+
+func sendMove(t *testing.T, conn *websocket.Conn, body moveBody) {
+	t.Helper()
+
+	if err := conn.WriteJSON(body); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/nolwn/go-hearts/bot"
+	"github.com/nolwn/go-hearts/hearts"
+	"github.com/nolwn/go-hearts/store"
+)
+
+// checkToken returns true if the token belongs to the seat of the game in the record.
+//
+// Each seat of a game has a token, which is kept with the game in the store so that it
+// outlives the server. A player proves which seat they are sitting in by presenting its
+// token, either as a bearer token in the Authorization header or in the token query
+// parameter for clients that can't set headers, like browsers opening a WebSocket or an
+// EventSource.
+func checkToken(record store.Record, seat int, token string) bool {
+	return tokenMatches(record.Seats[seat].Token, token)
+}
+
+// checkKibitzToken returns true if the token is the kibitz token of the game in the
+// record. Games that can't be kibitzed have no kibitz token.
+func checkKibitzToken(record store.Record, token string) bool {
+	return tokenMatches(record.KibitzToken, token)
+}
+
+// tokenMatches returns true if the token is the expected one. An empty token never
+// matches.
+func tokenMatches(expected string, token string) bool {
+	if token == "" || expected == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(token)) == 1
+}
+
+// issueTokens creates a new token for every seat of a game.
+func issueTokens() ([4]store.Seat, error) {
+	seats := [4]store.Seat{}
+
+	for i := range seats {
+		token, err := newToken()
+
+		if err != nil {
+			return seats, err
+		}
+
+		seats[i].Token = token
+	}
+
+	return seats, nil
+}
+
+// seatToken returns the token presented with the request, if there is one.
+func seatToken(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimPrefix(auth, "Bearer ")
+	}
+
+	return r.URL.Query().Get("token")
+}
+
+// authorize returns true if the request carries the seat's token. Otherwise, it responds
+// with 401 Unauthorized. A game that doesn't exist has no tokens, so it is turned away the
+// same way.
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request, id string, seat int) bool {
+	record, err := s.games.Load(id)
+
+	if err != nil && err != store.ErrNotFound {
+		writeStoreError(w, err)
+		return false
+	}
+
+	if err != nil || !checkToken(record, seat, seatToken(r)) {
+		writeError(w, http.StatusUnauthorized, errBadToken.Error())
+		return false
+	}
+
+	return true
+}
+
+// Revoke hands the seat over to the bot. The seat's token stops working, and anyone
+// playing or watching the game from the seat is disconnected. The bot takes the seat's
+// turns from then on, starting straight away if it is the seat's turn.
+func (s *Server) Revoke(id string, player int, replacement bot.Player) error {
+	if player < hearts.PlayerOne || player > hearts.PlayerFour {
+		return errBadPlayer
+	}
+
+	for {
+		record, err := s.games.Load(id)
+
+		if err != nil {
+			return err
+		}
+
+		record.Seats[player] = store.Seat{Bot: botKind(replacement)}
+		record, err = s.games.Save(record)
+
+		if err == store.ErrConflict {
+			continue // a move was saved in between, so revoke the seat in the newer game
+		}
+
+		if err != nil {
+			return err
+		}
+
+		s.live.kick(id, player)
+		s.live.publish(id, record.Version, record.Game)
+
+		if err := s.bots.add(record, player, replacement); err != nil {
+			return err
+		}
+
+		break
+	}
+
+	_, err := s.runBots(id)
+
+	return err
+}
+
+// newToken returns a random token that can't be guessed.
+func newToken() (string, error) {
+	b := make([]byte, 16)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,188 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/nolwn/go-hearts/hearts"
+	"github.com/nolwn/go-hearts/store"
+)
+
+// Server hosts games of Hearts over HTTP. Games are created, viewed and played through a
+// small JSON API:
+//
+//	POST /games                              creates a game, optionally from a seed
+//	GET  /games/{id}                         returns the game as a spectator sees it
+//	GET  /games/{id}/kibitz                  returns the game as a kibitzer sees it
+//	GET  /games/{id}/review                  reviews the tricks of the last round played
+//	GET  /games/{id}/scores                  returns the game's score sheet as CSV
+//	GET  /games/{id}/players/{player}        returns the Perspective of a player
+//	POST /games/{id}/players/{player}/moves  makes a move for a player
+//	GET  /games/{id}/players/{player}/live   plays a seat over a WebSocket
+//	GET  /games/{id}/players/{player}/events streams the game's events as a seat sees them
+//	GET  /games/{id}/events                  streams the game's events as a spectator sees them
+//
+// Players are identified by id, which starts at 1, the same way they are identified in a
+// Perspective. Creating a game returns a token for each seat, for whoever created it to
+// hand out, and every request made for a player has to carry that player's token. Games
+// whose rules allow kibitzing also return a kibitz token, which kibitzing requests have
+// to carry.
+type Server struct {
+
+	// games is where every hosted game is kept between requests.
+	games store.GameStore
+
+	// live is every seat that is playing over a WebSocket.
+	live *hub
+
+	// bots are the bots sitting in each game.
+	bots *bots
+}
+
+// errorBody is the JSON body returned with every error response. Code is only set when a
+// move broke a rule, and holds the hearts.ErrorCode of that rule.
+type errorBody struct {
+	Error string `json:"error"`
+	Code  string `json:"code,omitempty"`
+}
+
+// New creates a Server that keeps its games in the given store.
+func New(games store.GameStore) *Server {
+	return &Server{games: games, live: newHub(), bots: newBots()}
+}
+
+// ServeHTTP routes a request to the handler for its path.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	if parts[0] != "games" {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	switch len(parts) {
+	case 1:
+		route(w, r, http.MethodPost, s.createGame)
+
+	case 2:
+		route(w, r, http.MethodGet, func(w http.ResponseWriter, r *http.Request) {
+			s.spectateGame(w, r, parts[1])
+		})
+
+	case 3:
+		switch parts[2] {
+		case "events":
+			route(w, r, http.MethodGet, func(w http.ResponseWriter, r *http.Request) {
+				s.streamEvents(w, r, parts[1], hearts.Nobody)
+			})
+		case "kibitz":
+			route(w, r, http.MethodGet, func(w http.ResponseWriter, r *http.Request) {
+				s.kibitzGame(w, r, parts[1])
+			})
+		case "review":
+			route(w, r, http.MethodGet, func(w http.ResponseWriter, r *http.Request) {
+				s.reviewRound(w, r, parts[1])
+			})
+		case "scores":
+			route(w, r, http.MethodGet, func(w http.ResponseWriter, r *http.Request) {
+				s.scoreSheet(w, r, parts[1])
+			})
+		default:
+			writeError(w, http.StatusNotFound, "not found")
+		}
+
+	case 4, 5:
+		if parts[2] != "players" || (len(parts) == 5 && !isSeatRoute(parts[4])) {
+			writeError(w, http.StatusNotFound, "not found")
+			return
+		}
+
+		player, err := parsePlayer(parts[3])
+
+		if err != nil {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		switch {
+		case len(parts) == 4:
+			route(w, r, http.MethodGet, func(w http.ResponseWriter, r *http.Request) {
+				if s.authorize(w, r, parts[1], player) {
+					s.viewGame(w, r, parts[1], player)
+				}
+			})
+		case parts[4] == "moves":
+			route(w, r, http.MethodPost, func(w http.ResponseWriter, r *http.Request) {
+				if s.authorize(w, r, parts[1], player) {
+					s.playMove(w, r, parts[1], player)
+				}
+			})
+		case parts[4] == "events":
+			route(w, r, http.MethodGet, func(w http.ResponseWriter, r *http.Request) {
+				if s.authorize(w, r, parts[1], player) {
+					s.streamEvents(w, r, parts[1], player)
+				}
+			})
+		default:
+			route(w, r, http.MethodGet, func(w http.ResponseWriter, r *http.Request) {
+				s.liveGame(w, r, parts[1], player)
+			})
+		}
+
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+// isSeatRoute returns true if the last part of a path under a player is one the server
+// knows.
+func isSeatRoute(part string) bool {
+	return part == "moves" || part == "live" || part == "events"
+}
+
+// parsePlayer takes a player id from a path and returns the player index it refers to.
+func parsePlayer(id string) (int, error) {
+	n, err := strconv.Atoi(id)
+
+	if err != nil || n < 1 || n > 4 {
+		return hearts.Nobody, errBadPlayer
+	}
+
+	return n - 1, nil
+}
+
+// route calls handler if the request uses the given method. Otherwise, it responds with
+// 405 Method Not Allowed.
+func route(w http.ResponseWriter, r *http.Request, method string, handler http.HandlerFunc) {
+	if r.Method != method {
+		w.Header().Set("Allow", method)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	handler(w, r)
+}
+
+// writeStoreError responds to an error returned by the game store.
+func writeStoreError(w http.ResponseWriter, err error) {
+	switch err {
+	case store.ErrNotFound:
+		writeError(w, http.StatusNotFound, err.Error())
+	case store.ErrConflict:
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorBody{Error: message})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,482 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/nolwn/go-hearts/bot"
+	"github.com/nolwn/go-hearts/hearts"
+	"github.com/nolwn/go-hearts/store"
+)
+
+func TestServerCreateAndView(t *testing.T) {
+	ts := httptest.NewServer(New(store.NewMemoryStore()))
+	defer ts.Close()
+
+	created := create(t, ts, "")
+	id := created.ID
+
+	for player := 1; player <= 4; player++ {
+		per := viewGame(t, ts, created, player)
+
+		if len(per.Hand) != 13 {
+			t.Errorf("expected player %d to hold 13 cards, but they hold %d", player, len(per.Hand))
+		}
+
+		if per.Phase != "pass" {
+			t.Errorf("expected the pass phase but the game is in %s", per.Phase)
+		}
+	}
+
+	checkStatus(t, get(t, ts, "/games/nope"), http.StatusNotFound)
+	checkStatus(t, get(t, ts, "/games/"+id+"/players/5"), http.StatusNotFound)
+	checkStatus(t, get(t, ts, "/games/"+id+"/players/0"), http.StatusNotFound)
+	checkStatus(t, get(t, ts, "/games"), http.StatusMethodNotAllowed)
+}
+
+func TestServerMoves(t *testing.T) {
+	ts := httptest.NewServer(New(store.NewMemoryStore()))
+	defer ts.Close()
+
+	created := create(t, ts, "")
+	hand := viewGame(t, ts, created, 1).Hand
+
+	// malformed JSON
+	checkStatus(t, post(t, ts, movesPath(created.ID, 1), created.Tokens[0], []byte("{")), http.StatusBadRequest)
+
+	// a card that doesn't exist
+	checkStatus(t, pass(t, ts, created, 1, hearts.JSONCard{Suit: "Stars", Value: "Ace"}), http.StatusBadRequest)
+
+	// a move that doesn't exist
+	checkStatus(t, move(t, ts, created, 1, moveBody{Type: "claim"}), http.StatusBadRequest)
+
+	// a play without a card
+	checkStatus(t, move(t, ts, created, 1, moveBody{Type: "play"}), http.StatusBadRequest)
+
+	// passing the same card twice is against the rules
+	body := checkStatus(t, pass(t, ts, created, 1, hand[0], hand[0], hand[1]), http.StatusUnprocessableEntity)
+	checkCode(t, body, hearts.CodeDuplicateCard)
+
+	// playing a card during the pass phase doesn't fit the game
+	body = checkStatus(t, move(t, ts, created, 1, moveBody{Type: "play", Card: &hand[0]}), http.StatusConflict)
+	checkCode(t, body, hearts.CodeWrongPhase)
+
+	// passing three different cards is fine...
+	checkStatus(t, pass(t, ts, created, 1, hand[0], hand[1], hand[2]), http.StatusOK)
+
+	// ...but only once
+	body = checkStatus(t, pass(t, ts, created, 1, hand[3], hand[4], hand[5]), http.StatusConflict)
+	checkCode(t, body, hearts.CodeNotYourTurn)
+
+	per := viewGame(t, ts, created, 1)
+
+	if len(per.Hand) != 10 {
+		t.Errorf("expected player 1 to hold 10 cards after passing, but they hold %d", len(per.Hand))
+	}
+}
+
+func TestServerTokens(t *testing.T) {
+	s := New(store.NewMemoryStore())
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	created := create(t, ts, "")
+	hand := viewGame(t, ts, created, 2).Hand
+	body, _ := json.Marshal(moveBody{Type: "pass", Cards: hand[:3]})
+	path := "/games/" + created.ID + "/players/2"
+
+	// no token, another seat's token and a made up token are all turned away
+	for _, token := range []string{"", created.Tokens[2], "0123456789abcdef0123456789abcdef"} {
+		checkStatus(t, getAs(t, ts, path, token), http.StatusUnauthorized)
+		checkStatus(t, post(t, ts, path+"/moves", token, body), http.StatusUnauthorized)
+	}
+
+	// as is a game that doesn't exist
+	checkStatus(t, getAs(t, ts, "/games/nope/players/2", created.Tokens[1]), http.StatusUnauthorized)
+
+	// the token can be given in the query instead of the Authorization header
+	checkStatus(t, get(t, ts, path+"?token="+created.Tokens[1]), http.StatusOK)
+
+	// tokens are kept with the game, so they still work once the server is restarted
+	restarted := httptest.NewServer(New(s.games))
+	defer restarted.Close()
+	checkStatus(t, getAs(t, restarted, path, created.Tokens[1]), http.StatusOK)
+
+	// only the four seats can be handed to a bot
+	if err := s.Revoke(created.ID, 4, bot.NewHeuristic()); err != errBadPlayer {
+		t.Errorf("expected %q, but received %v", errBadPlayer, err)
+	}
+
+	// once the seat is handed to a bot, its token stops working
+	if err := s.Revoke(created.ID, hearts.PlayerTwo, bot.NewHeuristic()); err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	checkStatus(t, getAs(t, ts, path, created.Tokens[1]), http.StatusUnauthorized)
+	checkStatus(t, post(t, ts, path+"/moves", created.Tokens[1], body), http.StatusUnauthorized)
+
+	// the token is checked against the game the move is made in, not just the request
+	move, _ := moveBody{Type: "pass", Cards: hand[:3]}.move(hearts.PlayerTwo)
+
+	if _, err := s.apply(created.ID, move, created.Tokens[1]); err != errBadToken {
+		t.Errorf("expected %q, but received %v", errBadToken, err)
+	}
+
+	// but the other seats' tokens still do, and the bot has already passed for the seat
+	per := viewGame(t, ts, created, 1)
+
+	if !reflect.DeepEqual(per.HasPassed, []int{2}) {
+		t.Errorf("expected the bot to have passed for player 2, but %v have passed", per.HasPassed)
+	}
+}
+
+func TestServerSpectate(t *testing.T) {
+	ts := httptest.NewServer(New(store.NewMemoryStore()))
+	defer ts.Close()
+
+	if created := create(t, ts, ""); created.KibitzToken != "" {
+		t.Error("expected a game that can't be kibitzed not to have a kibitz token")
+	}
+
+	created := create(t, ts, `{"rules": {"kibitzDelay": 1}}`)
+
+	// spectators never see the hands, even when kibitzers can
+	view := spectateAs(t, ts, "/games/"+created.ID, "")
+
+	if len(view.Hands) != 0 || !reflect.DeepEqual(view.HandSizes, []int{13, 13, 13, 13}) {
+		t.Errorf("expected four hidden hands of 13 cards, but received %+v", view)
+	}
+
+	if view.Rules.KibitzDelay != 1 {
+		t.Errorf("expected kibitzers to be a trick behind, but received %+v", view.Rules)
+	}
+
+	// kibitzers have to have the game's kibitz token, which none of the seats have
+	path := "/games/" + created.ID + "/kibitz"
+
+	for _, token := range []string{"", created.Tokens[0]} {
+		checkStatus(t, getAs(t, ts, path, token), http.StatusUnauthorized)
+	}
+
+	if view = spectateAs(t, ts, path, created.KibitzToken); len(view.Hands) != 4 || len(view.Hands[0]) != 13 {
+		t.Errorf("expected a kibitzer to see four hands of 13 cards, but received %+v", view)
+	}
+}
+
+func TestServerSpectateDelayed(t *testing.T) {
+	ts := httptest.NewServer(New(store.NewMemoryStore()))
+	defer ts.Close()
+
+	created := create(t, ts, `{"rules": {"kibitzDelay": 1}}`)
+
+	for player := 1; player <= 4; player++ {
+		hand := viewGame(t, ts, created, player).Hand
+		checkStatus(t, pass(t, ts, created, player, hand[:3]...), http.StatusOK)
+	}
+
+	// play the first trick, and lead the second
+	for i := 0; i < 5; i++ {
+		per := viewGame(t, ts, created, 1)
+		turn := viewGame(t, ts, created, per.Turn)
+		checkStatus(t, move(t, ts, created, per.Turn, moveBody{Type: "play", Card: &turn.Legal[0]}), http.StatusOK)
+	}
+
+	// everyone watching sees the game as it was before the first trick was led
+	view := spectateAs(t, ts, "/games/"+created.ID, "")
+
+	if view.Phase != "play" || view.Trick != 1 || len(view.ThisTrick) != 0 || len(view.LastTrick) != 0 {
+		t.Errorf("expected the game before the first trick was led, but received %+v", view)
+	}
+
+	stream := openStream(t, ts, "/games/"+created.ID+"/events", "")
+	defer stream.Close()
+
+	for i := 0; i < 6; i++ {
+		stream.next(t)
+	}
+
+	if stream.last.Type != hearts.EventPassesResolved {
+		t.Fatalf("expected the passes to be resolved, but received %+v", stream.last)
+	}
+
+	// and the event stream holds back the plays as well
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/games/"+created.ID+"/events", nil)
+	req.Header.Set("Last-Event-ID", stream.id)
+	res, err := http.DefaultClient.Do(req)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, _ := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+
+	if len(b) != 0 {
+		t.Errorf("expected no more events to be sent, but received %s", b)
+	}
+}
+
+func TestServerReview(t *testing.T) {
+	s := New(store.NewMemoryStore())
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	created := create(t, ts, "")
+	checkStatus(t, get(t, ts, "/games/"+created.ID+"/review"), http.StatusNotFound)
+
+	// a game of bots is played to the end as soon as it is hosted
+	game := hearts.New(hearts.Options{Seed: 8})
+	game.Setup()
+
+	bots := map[int]bot.Player{}
+
+	for seat := hearts.PlayerOne; seat <= hearts.PlayerFour; seat++ {
+		bots[seat] = bot.NewHeuristic()
+	}
+
+	id, err := s.Host(&game, [4]string{}, bots)
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	res := get(t, ts, "/games/"+id+"/review")
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d but received %d", http.StatusOK, res.StatusCode)
+	}
+
+	var review hearts.RoundReview
+
+	if err := json.NewDecoder(res.Body).Decode(&review); err != nil {
+		t.Fatal(err)
+	}
+
+	// the game deals another round as the last one ends, so the last round played is
+	// the one before it
+	if review.Round != game.Round()-1 || len(review.Tricks) != 13 {
+		t.Errorf("expected the 13 tricks of round %d, but received %+v", game.Round()-1, review)
+	}
+}
+
+func TestServerScores(t *testing.T) {
+	s := New(store.NewMemoryStore())
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	game := hearts.New(hearts.Options{Seed: 8})
+	game.Setup()
+
+	bots := map[int]bot.Player{}
+
+	for seat := hearts.PlayerOne; seat <= hearts.PlayerFour; seat++ {
+		bots[seat] = bot.NewHeuristic()
+	}
+
+	id, err := s.Host(&game, [4]string{}, bots)
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	res := get(t, ts, "/games/"+id+"/scores")
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK || res.Header.Get("Content-Type") != "text/csv" {
+		t.Fatalf("expected a CSV but received status %d with %q", res.StatusCode, res.Header.Get("Content-Type"))
+	}
+
+	rows, err := csv.NewReader(res.Body).ReadAll()
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	// a header, and then a row for every round
+	if sheet := game.ScoreSheet(); len(rows) != len(sheet)+1 || rows[len(rows)-1][0] != strconv.Itoa(len(sheet)) {
+		t.Errorf("expected a row for each of the %d rounds, but received %v", len(sheet), rows)
+	}
+}
+
+func TestServerSeededGame(t *testing.T) {
+	ts := httptest.NewServer(New(store.NewMemoryStore()))
+	defer ts.Close()
+
+	first := viewGame(t, ts, create(t, ts, `{"seed": 99}`), 1)
+	second := viewGame(t, ts, create(t, ts, `{"seed": 99}`), 1)
+
+	if !reflect.DeepEqual(first.Hand, second.Hand) {
+		t.Error("expected games with the same seed to be dealt the same hands")
+	}
+}
+
+func TestServerRules(t *testing.T) {
+	ts := httptest.NewServer(New(store.NewMemoryStore()))
+	defer ts.Close()
+
+	per := viewGame(t, ts, create(t, ts, `{"rules": {"targetScore": 50}}`), 1)
+
+	if per.Rules.TargetScore != 50 || per.Rules.MoonShot != hearts.MoonShotAddToOthers {
+		t.Errorf("expected a game to 50 with standard moon shots, but received %+v", per.Rules)
+	}
+}
+
+// spectateAs gets a SpectatorView with the token.
+func spectateAs(t *testing.T, ts *httptest.Server, path string, token string) hearts.SpectatorView {
+	t.Helper()
+	res := getAs(t, ts, path, token)
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d but received %d", http.StatusOK, res.StatusCode)
+	}
+
+	var view hearts.SpectatorView
+
+	if err := json.NewDecoder(res.Body).Decode(&view); err != nil {
+		t.Fatal(err)
+	}
+
+	return view
+}
+
+func checkStatus(t *testing.T, res *http.Response, expected int) errorBody {
+	t.Helper()
+	defer res.Body.Close()
+
+	var body errorBody
+	json.NewDecoder(res.Body).Decode(&body)
+
+	if res.StatusCode != expected {
+		t.Errorf("expected status %d but received %d: %s", expected, res.StatusCode, body.Error)
+	}
+
+	return body
+}
+
+func checkCode(t *testing.T, body errorBody, expected hearts.ErrorCode) {
+	t.Helper()
+
+	if body.Code != string(expected) {
+		t.Errorf("expected code %s but received %q", expected, body.Code)
+	}
+}
+
+// create creates a game and returns the response, with the game's id and seat tokens.
+func create(t *testing.T, ts *httptest.Server, body string) createdBody {
+	t.Helper()
+	res, err := http.Post(ts.URL+"/games", "application/json", bytes.NewBufferString(body))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		t.Fatalf("expected status %d but received %d", http.StatusCreated, res.StatusCode)
+	}
+
+	var created createdBody
+
+	if err := json.NewDecoder(res.Body).Decode(&created); err != nil {
+		t.Fatal(err)
+	}
+
+	return created
+}
+
+func get(t *testing.T, ts *httptest.Server, path string) *http.Response {
+	t.Helper()
+
+	return getAs(t, ts, path, "")
+}
+
+// getAs makes a GET request with the token as its bearer token.
+func getAs(t *testing.T, ts *httptest.Server, path string, token string) *http.Response {
+	t.Helper()
+
+	return request(t, ts, http.MethodGet, path, token, nil)
+}
+
+// post makes a POST request with the token as its bearer token.
+func post(t *testing.T, ts *httptest.Server, path string, token string, body []byte) *http.Response {
+	t.Helper()
+
+	return request(t, ts, http.MethodPost, path, token, body)
+}
+
+func request(t *testing.T, ts *httptest.Server, method string, path string, token string, body []byte) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, ts.URL+path, bytes.NewReader(body))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return res
+}
+
+// move makes a move for the player, with the player's token.
+func move(t *testing.T, ts *httptest.Server, created createdBody, player int, body moveBody) *http.Response {
+	t.Helper()
+	b, err := json.Marshal(body)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return post(t, ts, movesPath(created.ID, player), created.Tokens[player-1], b)
+}
+
+func movesPath(id string, player int) string {
+	return "/games/" + id + "/players/" + strconv.Itoa(player) + "/moves"
+}
+
+func pass(t *testing.T, ts *httptest.Server, created createdBody, player int, cards ...hearts.JSONCard) *http.Response {
+	t.Helper()
+
+	return move(t, ts, created, player, moveBody{Type: "pass", Cards: cards})
+}
+
+// viewGame returns the player's Perspective, fetched with the player's token.
+func viewGame(t *testing.T, ts *httptest.Server, created createdBody, player int) hearts.Perspective {
+	t.Helper()
+	res := getAs(t, ts, "/games/"+created.ID+"/players/"+strconv.Itoa(player), created.Tokens[player-1])
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d but received %d", http.StatusOK, res.StatusCode)
+	}
+
+	var per hearts.Perspective
+
+	if err := json.NewDecoder(res.Body).Decode(&per); err != nil {
+		t.Fatal(err)
+	}
+
+	return per
+}
@@ -0,0 +1,187 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/nolwn/go-hearts/hearts"
+)
+
+var errBadEventID = errors.New("Last-Event-ID must be the id of an event that was sent")
+
+// eventBody is the JSON data of an event sent over an event stream. It is a hearts.Event
+// with players given by id, the way they are in a Perspective, and only the cards the
+// viewer is allowed to see. Seat is left out when the event isn't about one seat.
+type eventBody struct {
+	Type        hearts.EventType  `json:"type"`
+	Round       int               `json:"round"`
+	Trick       int               `json:"trick,omitempty"`
+	Seat        int               `json:"seat,omitempty"`
+	Card        *hearts.JSONCard  `json:"card,omitempty"`
+	Cards       []hearts.JSONCard `json:"cards,omitempty"`
+	Suit        string            `json:"suit,omitempty"`
+	Points      int               `json:"points,omitempty"`
+	RoundPoints []int             `json:"roundPoints,omitempty"`
+	Scores      []int             `json:"scores,omitempty"`
+	Winners     []int             `json:"winners,omitempty"`
+}
+
+// streamEvents streams the game's events to the viewer as Server-Sent Events, starting
+// with the first event in the game's log and carrying on as moves are made. The viewer is
+// either a seat, or Nobody for a spectator. Each event is sent with its place in the log
+// as its id and its type as its name, so a client that reconnects with a Last-Event-ID
+// header carries on with the event after that one. The server never takes moves back, so
+// an event's place in the log never changes; Hearts.Undo cuts the end of the log, and
+// would have to close every stream of the game before it could be offered here.
+//
+// A seat's stream ends once its token is revoked.
+//
+// Cards are only sent to viewers who could see them at the table: a deal only shows a
+// seat its own hand, and a pass only shows the seat that passed the cards. Cards that are
+// played are shown to everyone. If the game's rules let kibitzers see the hands,
+// spectators are only sent the events that lead up to the game they are shown by
+// spectateGame.
+func (s *Server) streamEvents(w http.ResponseWriter, r *http.Request, id string, viewer int) {
+	flusher, ok := w.(http.Flusher)
+
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming isn't supported")
+		return
+	}
+
+	next := 0
+
+	if last := r.Header.Get("Last-Event-ID"); last != "" {
+		n, err := strconv.Atoi(last)
+
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, errBadEventID.Error())
+			return
+		}
+
+		next = n + 1
+	}
+
+	// watch before loading, so that no move can slip in between
+	changed := s.live.watch(id)
+	defer s.live.unwatch(id, changed)
+
+	record, err := s.games.Load(id)
+
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	keepAlive := time.NewTicker(pingPeriod)
+	defer keepAlive.Stop()
+
+	for {
+		events := record.Game.Events()
+
+		if viewer == hearts.Nobody {
+			events = record.Game.SpectatorEvents()
+		}
+
+		for ; next < len(events); next++ {
+			if err := writeEvent(w, next, events[next], viewer); err != nil {
+				return
+			}
+		}
+
+		flusher.Flush()
+
+		select {
+		case <-changed:
+			if record, err = s.games.Load(id); err != nil {
+				return
+			}
+
+			if viewer != hearts.Nobody && !checkToken(record, viewer, seatToken(r)) {
+				return // the seat's token has been revoked
+			}
+
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// writeEvent writes one event of a stream, as the viewer sees it.
+func writeEvent(w http.ResponseWriter, n int, e hearts.Event, viewer int) error {
+	b, err := json.Marshal(eventView(e, viewer))
+
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", n, e.Type, b)
+
+	return err
+}
+
+// eventView returns the event as the viewer sees it.
+func eventView(e hearts.Event, viewer int) eventBody {
+	body := eventBody{
+		Type:        e.Type,
+		Round:       e.Round,
+		Trick:       e.Trick,
+		Suit:        e.Suit,
+		Points:      e.Points,
+		RoundPoints: e.RoundPoints,
+		Scores:      e.Scores,
+	}
+
+	if e.Seat != hearts.Nobody {
+		body.Seat = e.Seat + 1
+	}
+
+	for _, winner := range e.Winners {
+		body.Winners = append(body.Winners, winner+1)
+	}
+
+	if e.Card != nil {
+		card := jsonCard(*e.Card)
+		body.Card = &card
+	}
+
+	var cards []hearts.Card
+
+	switch e.Type {
+	case hearts.EventDeal:
+		if viewer != hearts.Nobody && len(e.Cards) == 52 {
+			cards = e.Cards[viewer*13 : (viewer+1)*13]
+		}
+
+	case hearts.EventPass:
+		if viewer == e.Seat {
+			cards = e.Cards
+		}
+
+	default:
+		cards = e.Cards
+	}
+
+	for _, c := range cards {
+		body.Cards = append(body.Cards, jsonCard(c))
+	}
+
+	return body
+}
+
+func jsonCard(c hearts.Card) hearts.JSONCard {
+	return hearts.JSONCard{Suit: c.Suit(), Value: c.Value()}
+}
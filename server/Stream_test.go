@@ -0,0 +1,222 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nolwn/go-hearts/hearts"
+	"github.com/nolwn/go-hearts/store"
+)
+
+func TestStreamSpectator(t *testing.T) {
+	ts := httptest.NewServer(New(store.NewMemoryStore()))
+	defer ts.Close()
+
+	created := create(t, ts, "")
+	hand := viewGame(t, ts, created, 1).Hand
+	checkStatus(t, pass(t, ts, created, 1, hand[:3]...), http.StatusOK)
+
+	stream := openStream(t, ts, "/games/"+created.ID+"/events", "")
+	defer stream.Close()
+
+	// spectators see that the cards were dealt and passed, but not which cards
+	for _, expected := range []hearts.EventType{hearts.EventDeal, hearts.EventPass} {
+		e := stream.next(t)
+
+		if e.Type != expected || len(e.Cards) != 0 {
+			t.Errorf("expected a %s event without cards, but received %+v", expected, e)
+		}
+	}
+
+	if e := stream.last; e.Seat != 1 {
+		t.Errorf("expected player 1 to have passed, but player %d did", e.Seat)
+	}
+}
+
+func TestStreamSeat(t *testing.T) {
+	ts := httptest.NewServer(New(store.NewMemoryStore()))
+	defer ts.Close()
+
+	created := create(t, ts, "")
+	path := "/games/" + created.ID + "/players/2/events"
+
+	res := get(t, ts, path+"?token="+created.Tokens[0])
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected status %d but received %d", http.StatusUnauthorized, res.StatusCode)
+	}
+
+	stream := openStream(t, ts, path+"?token="+created.Tokens[1], "")
+	defer stream.Close()
+
+	// a seat is shown its own hand
+	hand := viewGame(t, ts, created, 2).Hand
+
+	if e := stream.next(t); !sameCards(e.Cards, hand) {
+		t.Errorf("expected player 2 to be dealt %v but was shown %v", hand, e.Cards)
+	}
+
+	// and events as they happen, with only its own passed cards
+	passes := map[int][]hearts.JSONCard{}
+
+	for _, player := range []int{1, 2} {
+		passes[player] = viewGame(t, ts, created, player).Hand[:3]
+		checkStatus(t, pass(t, ts, created, player, passes[player]...), http.StatusOK)
+	}
+
+	if e := stream.next(t); e.Type != hearts.EventPass || e.Seat != 1 || len(e.Cards) != 0 {
+		t.Errorf("expected player 1's pass without its cards, but received %+v", e)
+	}
+
+	if e := stream.next(t); e.Type != hearts.EventPass || e.Seat != 2 || !sameCards(e.Cards, passes[2]) {
+		t.Errorf("expected player 2's pass of %v, but received %+v", passes[2], e)
+	}
+}
+
+func TestStreamResume(t *testing.T) {
+	ts := httptest.NewServer(New(store.NewMemoryStore()))
+	defer ts.Close()
+
+	created := create(t, ts, "")
+	id := created.ID
+
+	for player := 1; player <= 4; player++ {
+		hand := viewGame(t, ts, created, player).Hand
+		checkStatus(t, pass(t, ts, created, player, hand[:3]...), http.StatusOK)
+	}
+
+	// the client has seen the deal and the first pass
+	stream := openStream(t, ts, "/games/"+id+"/events", "1")
+	defer stream.Close()
+
+	if e := stream.next(t); stream.id != "2" || e.Type != hearts.EventPass || e.Seat != 2 {
+		t.Errorf("expected to resume with event 2, player 2's pass, but received event %s: %+v", stream.id, e)
+	}
+
+	res := getWithHeader(t, ts, "/games/"+id+"/events", "Last-Event-ID", "first")
+	defer res.Body.Close()
+	checkStatus(t, res, http.StatusBadRequest)
+}
+
+// eventStream reads Server-Sent Events from a response.
+type eventStream struct {
+	res    *http.Response
+	reader *bufio.Reader
+	id     string
+	last   eventBody
+}
+
+func openStream(t *testing.T, ts *httptest.Server, path string, lastEventID string) *eventStream {
+	t.Helper()
+	res := getWithHeader(t, ts, path, "Last-Event-ID", lastEventID)
+
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		t.Fatalf("expected status %d but received %d", http.StatusOK, res.StatusCode)
+	}
+
+	if ct := res.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("expected an event stream but received %q", ct)
+	}
+
+	return &eventStream{res: res, reader: bufio.NewReader(res.Body)}
+}
+
+func (s *eventStream) Close() {
+	s.res.Body.Close()
+}
+
+// next reads the next event from the stream, skipping comments.
+func (s *eventStream) next(t *testing.T) eventBody {
+	t.Helper()
+	done := make(chan error, 1)
+	var name, data string
+
+	go func() {
+		for {
+			line, err := s.reader.ReadString('\n')
+
+			if err != nil {
+				done <- err
+				return
+			}
+
+			line = strings.TrimSuffix(line, "\n")
+
+			switch {
+			case line == "" && data != "":
+				done <- nil
+				return
+			case strings.HasPrefix(line, "id: "):
+				s.id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				name = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(2 * time.Second):
+		s.Close()
+		t.Fatal("expected an event but none was sent")
+	}
+
+	s.last = eventBody{}
+
+	if err := json.Unmarshal([]byte(data), &s.last); err != nil {
+		t.Fatal(err)
+	}
+
+	if name != string(s.last.Type) {
+		t.Errorf("expected the event to be named %s but it was %s", s.last.Type, name)
+	}
+
+	return s.last
+}
+
+func getWithHeader(t *testing.T, ts *httptest.Server, path string, key string, value string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, ts.URL+path, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value != "" {
+		req.Header.Set(key, value)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return res
+}
+
+func sameCards(a []hearts.JSONCard, b []hearts.JSONCard) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
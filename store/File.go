@@ -0,0 +1,242 @@
+package store
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// fileExt is the extension given to every game file.
+const fileExt = ".json"
+
+// FileStore keeps each game in its own JSON file inside a directory. Files are replaced
+// atomically, so a crash while saving leaves either the old game or the new one.
+//
+// Versions are only checked within a single FileStore. Two processes sharing a directory
+// can still overwrite each other's saves.
+type FileStore struct {
+
+	// dir is the directory the games are kept in.
+	dir string
+
+	// mu makes checking the version and writing the file a single step.
+	mu sync.Mutex
+}
+
+// fileEntry is the contents of a game file.
+type fileEntry struct {
+	Version     int             `json:"version"`
+	Game        json.RawMessage `json:"game"`
+	Seats       [4]Seat         `json:"seats"`
+	KibitzToken string          `json:"kibitzToken,omitempty"`
+}
+
+// NewFileStore creates a FileStore that keeps games in dir. The directory is created if
+// it doesn't exist.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &FileStore{dir: dir}, nil
+}
+
+// Create stores a new game in a new file.
+func (s *FileStore) Create(record Record) (Record, error) {
+	if record.Game == nil {
+		return Record{}, ErrNoGame
+	}
+
+	id, err := newID()
+
+	if err != nil {
+		return Record{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record.ID = id
+	record.Version = 1
+
+	if err := s.write(record); err != nil {
+		return Record{}, err
+	}
+
+	return record, nil
+}
+
+// Delete removes a game's file.
+func (s *FileStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path, err := s.path(id)
+
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+
+	if os.IsNotExist(err) {
+		return ErrNotFound
+	}
+
+	return err
+}
+
+// List returns the ids of every game file in the directory.
+func (s *FileStore) List() ([]string, error) {
+	files, err := ioutil.ReadDir(s.dir)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(files))
+
+	for _, f := range files {
+		name := f.Name()
+
+		if !f.IsDir() && strings.HasSuffix(name, fileExt) {
+			ids = append(ids, strings.TrimSuffix(name, fileExt))
+		}
+	}
+
+	return ids, nil
+}
+
+// Load reads a game from its file.
+func (s *FileStore) Load(id string) (Record, error) {
+	entry, err := s.read(id)
+
+	if err != nil {
+		return Record{}, err
+	}
+
+	game, err := decodeGame(entry.Game)
+
+	if err != nil {
+		return Record{}, err
+	}
+
+	return Record{
+		ID:          id,
+		Version:     entry.Version,
+		Game:        game,
+		Seats:       entry.Seats,
+		KibitzToken: entry.KibitzToken,
+	}, nil
+}
+
+// Save writes a game to its file if it hasn't been saved since it was loaded.
+func (s *FileStore) Save(record Record) (Record, error) {
+	if record.Game == nil {
+		return Record{}, ErrNoGame
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, err := s.read(record.ID)
+
+	if err != nil {
+		return Record{}, err
+	}
+
+	if entry.Version != record.Version {
+		return Record{}, ErrConflict
+	}
+
+	record.Version++
+
+	if err := s.write(record); err != nil {
+		return Record{}, err
+	}
+
+	return record, nil
+}
+
+// path returns the path of the file for a game. Only ids that newID could have generated
+// are accepted so that an id can never point outside of the directory.
+func (s *FileStore) path(id string) (string, error) {
+	if _, err := hex.DecodeString(id); err != nil || id == "" {
+		return "", ErrNotFound
+	}
+
+	return filepath.Join(s.dir, id+fileExt), nil
+}
+
+// read returns the contents of a game's file.
+func (s *FileStore) read(id string) (fileEntry, error) {
+	var entry fileEntry
+	path, err := s.path(id)
+
+	if err != nil {
+		return entry, err
+	}
+
+	b, err := ioutil.ReadFile(path)
+
+	if os.IsNotExist(err) {
+		return entry, ErrNotFound
+	} else if err != nil {
+		return entry, err
+	}
+
+	err = json.Unmarshal(b, &entry)
+
+	return entry, err
+}
+
+// write replaces a game's file with the record. The record is written to a temporary file
+// first, which is then renamed over the old one.
+func (s *FileStore) write(record Record) error {
+	id := record.ID
+	path, err := s.path(id)
+
+	if err != nil {
+		return err
+	}
+
+	g, err := record.Game.MarshalJSON()
+
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(fileEntry{
+		Version:     record.Version,
+		Game:        g,
+		Seats:       record.Seats,
+		KibitzToken: record.KibitzToken,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(s.dir, id+".*.tmp")
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name()) // fails harmlessly once the file has been renamed
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
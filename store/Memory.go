@@ -0,0 +1,154 @@
+package store
+
+import "sync"
+
+// MemoryStore keeps games in memory. Games are kept in their encoded form so that a
+// loaded game never shares any state with the store or with other loaded copies.
+type MemoryStore struct {
+
+	// games maps game ids to their stored form.
+	games map[string]memoryEntry
+
+	// mu guards games.
+	mu sync.Mutex
+}
+
+type memoryEntry struct {
+	version     int
+	game        []byte
+	seats       [4]Seat
+	kibitzToken string
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{games: make(map[string]memoryEntry)}
+}
+
+// Create stores a new game.
+func (s *MemoryStore) Create(record Record) (Record, error) {
+	if record.Game == nil {
+		return Record{}, ErrNoGame
+	}
+
+	id, err := newID()
+
+	if err != nil {
+		return Record{}, err
+	}
+
+	record.ID = id
+	record.Version = 1
+	entry, err := newMemoryEntry(record, 1)
+
+	if err != nil {
+		return Record{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.games[id] = entry
+
+	return record, nil
+}
+
+// Delete removes a game.
+func (s *MemoryStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.games[id]; !ok {
+		return ErrNotFound
+	}
+
+	delete(s.games, id)
+
+	return nil
+}
+
+// List returns the ids of every game.
+func (s *MemoryStore) List() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]string, 0, len(s.games))
+
+	for id := range s.games {
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// Load returns a copy of a stored game.
+func (s *MemoryStore) Load(id string) (Record, error) {
+	s.mu.Lock()
+	entry, ok := s.games[id]
+	s.mu.Unlock()
+
+	if !ok {
+		return Record{}, ErrNotFound
+	}
+
+	game, err := decodeGame(entry.game)
+
+	if err != nil {
+		return Record{}, err
+	}
+
+	return Record{
+		ID:          id,
+		Version:     entry.version,
+		Game:        game,
+		Seats:       entry.seats,
+		KibitzToken: entry.kibitzToken,
+	}, nil
+}
+
+// Save stores a game if it hasn't been saved since it was loaded.
+func (s *MemoryStore) Save(record Record) (Record, error) {
+	if record.Game == nil {
+		return Record{}, ErrNoGame
+	}
+
+	saved, err := newMemoryEntry(record, record.Version+1)
+
+	if err != nil {
+		return Record{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.games[record.ID]
+
+	if !ok {
+		return Record{}, ErrNotFound
+	}
+
+	if entry.version != record.Version {
+		return Record{}, ErrConflict
+	}
+
+	record.Version++
+	s.games[record.ID] = saved
+
+	return record, nil
+}
+
+// newMemoryEntry returns the stored form of the record, at the given version.
+func newMemoryEntry(record Record, version int) (memoryEntry, error) {
+	b, err := record.Game.MarshalJSON()
+
+	if err != nil {
+		return memoryEntry{}, err
+	}
+
+	return memoryEntry{
+		version:     version,
+		game:        b,
+		seats:       record.Seats,
+		kibitzToken: record.KibitzToken,
+	}, nil
+}
@@ -0,0 +1,269 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	_ "github.com/mattn/go-sqlite3" // registers the sqlite3 driver
+)
+
+// SQLiteStore keeps games in an embedded SQLite database. Versions are checked by the
+// database itself, so several processes can safely share one database file.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore opens, or creates, the SQLite database at path. Use ":memory:" for a
+// database that only lives as long as the store.
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite3", path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// an in-memory database only exists on the connection that created it
+	if path == ":memory:" {
+		db.SetMaxOpenConns(1)
+	}
+
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS games (
+		id           TEXT PRIMARY KEY,
+		version      INTEGER NOT NULL,
+		game         BLOB NOT NULL,
+		seats        BLOB,
+		kibitz_token TEXT
+	)`)
+
+	if err == nil {
+		err = addColumns(db)
+	}
+
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &SQLiteStore{db: db}, nil
+}
+
+// addedColumns are the columns added to the games table after it was first made, along
+// with their types.
+var addedColumns = []struct{ name, kind string }{
+	{"seats", "BLOB"},
+	{"kibitz_token", "TEXT"},
+}
+
+// addColumns adds the columns that databases made by older versions of the store are
+// missing. Games in those databases are left with empty seats and no kibitz token.
+func addColumns(db *sql.DB) error {
+	rows, err := db.Query(`PRAGMA table_info(games)`)
+
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+	columns := map[string]bool{}
+
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, kind string
+		var value interface{}
+
+		if err := rows.Scan(&cid, &name, &kind, &notNull, &value, &pk); err != nil {
+			return err
+		}
+
+		columns[name] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, c := range addedColumns {
+		if !columns[c.name] {
+			if _, err := db.Exec(`ALTER TABLE games ADD COLUMN ` + c.name + ` ` + c.kind); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Close closes the database.
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// Create inserts a new game.
+func (s *SQLiteStore) Create(record Record) (Record, error) {
+	if record.Game == nil {
+		return Record{}, ErrNoGame
+	}
+
+	id, err := newID()
+
+	if err != nil {
+		return Record{}, err
+	}
+
+	b, err := record.Game.MarshalJSON()
+
+	if err != nil {
+		return Record{}, err
+	}
+
+	sb, err := json.Marshal(record.Seats)
+
+	if err != nil {
+		return Record{}, err
+	}
+
+	_, err = s.db.Exec(
+		`INSERT INTO games (id, version, game, seats, kibitz_token) VALUES (?, 1, ?, ?, ?)`,
+		id,
+		b,
+		sb,
+		record.KibitzToken,
+	)
+
+	if err != nil {
+		return Record{}, err
+	}
+
+	record.ID = id
+	record.Version = 1
+
+	return record, nil
+}
+
+// Delete removes a game.
+func (s *SQLiteStore) Delete(id string) error {
+	res, err := s.db.Exec(`DELETE FROM games WHERE id = ?`, id)
+
+	if err != nil {
+		return err
+	}
+
+	return requireRow(res, ErrNotFound)
+}
+
+// List returns the ids of every game.
+func (s *SQLiteStore) List() ([]string, error) {
+	rows, err := s.db.Query(`SELECT id FROM games`)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	ids := []string{}
+
+	for rows.Next() {
+		var id string
+
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// Load selects a game.
+func (s *SQLiteStore) Load(id string) (Record, error) {
+	var version int
+	var b, sb []byte
+	var kibitzToken sql.NullString
+
+	err := s.db.QueryRow(`SELECT version, game, seats, kibitz_token FROM games WHERE id = ?`, id).
+		Scan(&version, &b, &sb, &kibitzToken)
+
+	if err == sql.ErrNoRows {
+		return Record{}, ErrNotFound
+	} else if err != nil {
+		return Record{}, err
+	}
+
+	game, err := decodeGame(b)
+
+	if err != nil {
+		return Record{}, err
+	}
+
+	record := Record{ID: id, Version: version, Game: game, KibitzToken: kibitzToken.String}
+
+	// games stored before seats were kept have none
+	if len(sb) > 0 {
+		if err := json.Unmarshal(sb, &record.Seats); err != nil {
+			return Record{}, err
+		}
+	}
+
+	return record, nil
+}
+
+// Save updates a game if its version hasn't changed since it was loaded.
+func (s *SQLiteStore) Save(record Record) (Record, error) {
+	if record.Game == nil {
+		return Record{}, ErrNoGame
+	}
+
+	b, err := record.Game.MarshalJSON()
+
+	if err != nil {
+		return Record{}, err
+	}
+
+	sb, err := json.Marshal(record.Seats)
+
+	if err != nil {
+		return Record{}, err
+	}
+
+	res, err := s.db.Exec(
+		`UPDATE games SET version = version + 1, game = ?, seats = ?, kibitz_token = ? WHERE id = ? AND version = ?`,
+		b,
+		sb,
+		record.KibitzToken,
+		record.ID,
+		record.Version,
+	)
+
+	if err != nil {
+		return Record{}, err
+	}
+
+	// no row was updated, so either the game is gone or its version moved on
+	if err := requireRow(res, ErrConflict); err != nil {
+		if _, loadErr := s.Load(record.ID); loadErr == ErrNotFound {
+			return Record{}, ErrNotFound
+		}
+
+		return Record{}, err
+	}
+
+	record.Version++
+
+	return record, nil
+}
+
+// requireRow returns notAffected if the statement didn't affect any rows.
+func requireRow(res sql.Result, notAffected error) error {
+	n, err := res.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return notAffected
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+// Package store keeps games of Hearts between requests. Every store hands out Records,
+// which pair a game with a version number. Saving a Record that is out of date fails with
+// ErrConflict, so two requests that play into the same game can't silently overwrite
+// each other.
+package store
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+
+	"github.com/nolwn/go-hearts/hearts"
+)
+
+var (
+	// ErrConflict is returned by Save when the game was saved by someone else after the
+	// Record being saved was loaded. The game should be loaded again and the change
+	// retried.
+	ErrConflict = errors.New("the game has been changed since it was loaded")
+
+	// ErrNotFound is returned when there is no game with the given id.
+	ErrNotFound = errors.New("game not found")
+
+	// ErrNoGame is returned by Create and Save when the Record doesn't have a game.
+	ErrNoGame = errors.New("the record has no game")
+)
+
+// GameStore stores games of Hearts. Implementations must be safe for concurrent use.
+type GameStore interface {
+
+	// Create stores the Record as a new game, and returns it with a newly generated id and
+	// a Version of 1. The ID and Version it is given are ignored. It returns ErrNoGame if
+	// the Record doesn't have a game.
+	Create(record Record) (Record, error)
+
+	// Delete removes a game. It returns ErrNotFound if there is no game with that id.
+	Delete(id string) error
+
+	// List returns the ids of every stored game, in no particular order.
+	List() ([]string, error)
+
+	// Load returns the Record of a stored game. The game in the Record belongs to the
+	// caller; changing it has no effect on the store until it is saved.
+	Load(id string) (Record, error)
+
+	// Save stores everything in the Record. It only succeeds if the Record's Version
+	// matches the Version that is currently stored, otherwise it returns ErrConflict. The
+	// returned Record has the new Version and should be used for the next Save. It
+	// returns ErrNoGame if the Record doesn't have a game.
+	Save(record Record) (Record, error)
+}
+
+// Record is a stored game along with the version it was stored at.
+type Record struct {
+
+	// ID identifies the game in the store.
+	ID string
+
+	// Version is incremented each time the game is saved.
+	Version int
+
+	// Game is the game itself.
+	Game *hearts.Hearts
+
+	// Seats are who is sitting in each seat of the game, by player index.
+	Seats [4]Seat
+
+	// KibitzToken proves that a request comes from a kibitzer, who watches the game
+	// without playing and sees every hand. A game with no kibitz token can't be kibitzed.
+	KibitzToken string
+}
+
+// Seat is what the store keeps about one seat of a game.
+type Seat struct {
+
+	// Token proves that a request comes from whoever is sitting in the seat. A seat with
+	// no token can't be played by anyone.
+	Token string `json:"token,omitempty"`
+
+	// Bot names the kind of bot playing the seat, if a bot is, so that it can be sat down
+	// again when the game is loaded somewhere that doesn't have it.
+	Bot string `json:"bot,omitempty"`
+}
+
+// newID returns a random identifier for a game. Ids are hex strings, so they are safe to
+// use as file names.
+func newID() (string, error) {
+	b := make([]byte, 8)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// decodeGame restores a game from its stored JSON.
+func decodeGame(b []byte) (*hearts.Hearts, error) {
+	game := &hearts.Hearts{}
+
+	if err := game.UnmarshalJSON(b); err != nil {
+		return nil, err
+	}
+
+	return game, nil
+}
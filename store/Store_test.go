@@ -0,0 +1,250 @@
+package store
+
+import (
+	"bytes"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/nolwn/go-hearts/hearts"
+)
+
+func TestMemoryStore(t *testing.T) {
+	checkStore(t, NewMemoryStore())
+}
+
+func TestFileStore(t *testing.T) {
+	s, err := NewFileStore(filepath.Join(t.TempDir(), "games"))
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	checkStore(t, s)
+
+	if _, err := s.Load("../games"); err != ErrNotFound {
+		t.Errorf("expected an id with a path in it to be not found, but received: %v", err)
+	}
+}
+
+func TestSQLiteStore(t *testing.T) {
+	s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "games.db"))
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	defer s.Close()
+
+	checkStore(t, s)
+}
+
+func TestSQLiteStoreUpgrade(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "games.db")
+	db, err := sql.Open("sqlite3", path)
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	game := hearts.New()
+	game.Setup()
+	b, _ := game.MarshalJSON()
+
+	// the table as it was before seats were stored
+	_, err = db.Exec(`CREATE TABLE games (id TEXT PRIMARY KEY, version INTEGER NOT NULL, game BLOB NOT NULL)`)
+
+	if err == nil {
+		_, err = db.Exec(`INSERT INTO games (id, version, game) VALUES ('00', 1, ?)`, b)
+	}
+
+	db.Close()
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	s, err := NewSQLiteStore(path)
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	defer s.Close()
+
+	record, err := s.Load("00")
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	if record.Seats != [4]Seat{} || record.KibitzToken != "" {
+		t.Errorf("expected an older game to have empty seats and no kibitz token but it has %+v", record)
+	}
+
+	record.Seats[hearts.PlayerOne].Token = "one"
+	record.KibitzToken = "kibitz"
+
+	if _, err := s.Save(record); err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	if loaded, _ := s.Load("00"); loaded.Seats != record.Seats || loaded.KibitzToken != "kibitz" {
+		t.Errorf("expected the seats and kibitz token to be saved, but received %+v", loaded)
+	}
+}
+
+// checkStore runs a GameStore through everything a GameStore is expected to do.
+func checkStore(t *testing.T, s GameStore) {
+	t.Helper()
+
+	game := hearts.New()
+	game.Setup()
+
+	created, err := s.Create(Record{
+		ID:          "ignored",
+		Game:        &game,
+		Seats:       [4]Seat{{Token: "one"}, {Token: "two"}, {Bot: "heuristic"}},
+		KibitzToken: "kibitz",
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	if created.Version != 1 || created.ID == "ignored" {
+		t.Errorf("expected a new game to have a new id and version 1 but it has %+v", created)
+	}
+
+	// two requests load the same game
+	first, err := s.Load(created.ID)
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	second, err := s.Load(created.ID)
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	checkSameGame(t, created.Game, first.Game)
+
+	if first.Seats != created.Seats || first.KibitzToken != created.KibitzToken {
+		t.Errorf("expected seats %v and kibitz token %q but received %+v", created.Seats, created.KibitzToken, first)
+	}
+
+	// the first one plays, gives up a seat and a new kibitz token, and saves
+	hand := first.Game.Players[hearts.PlayerOne].Hand
+	first.Game.Play(hearts.PlayerOne, hand[0], hand[1], hand[2])
+	first.Seats[hearts.PlayerTwo] = Seat{}
+	first.KibitzToken = "another"
+
+	if len(second.Game.Players[hearts.PlayerOne].Hand) != 13 {
+		t.Error("expected loaded games not to share any state")
+	}
+
+	saved, err := s.Save(first)
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	if saved.Version != 2 {
+		t.Errorf("expected a saved game to have version 2 but it has %d", saved.Version)
+	}
+
+	// the second one is now out of date
+	hand = second.Game.Players[hearts.PlayerTwo].Hand
+	second.Game.Play(hearts.PlayerTwo, hand[0], hand[1], hand[2])
+
+	if _, err := s.Save(second); err != ErrConflict {
+		t.Errorf("expected ErrConflict but received: %v", err)
+	}
+
+	// a record without a game can't be stored
+	if _, err := s.Create(Record{}); err != ErrNoGame {
+		t.Errorf("expected ErrNoGame but received: %v", err)
+	}
+
+	if _, err := s.Save(Record{ID: saved.ID, Version: saved.Version}); err != ErrNoGame {
+		t.Errorf("expected ErrNoGame but received: %v", err)
+	}
+
+	loaded, err := s.Load(created.ID)
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	checkSameGame(t, first.Game, loaded.Game)
+
+	if loaded.Seats != first.Seats || loaded.KibitzToken != first.KibitzToken {
+		t.Errorf("expected seats %v and kibitz token %q but received %+v", first.Seats, first.KibitzToken, loaded)
+	}
+
+	// saving again with the returned record works
+	if _, err := s.Save(saved); err != nil {
+		t.Errorf("expected no error but received: %s", err)
+	}
+
+	other, err := s.Create(Record{Game: &game})
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	ids, err := s.List()
+
+	if err != nil {
+		t.Fatalf("expected no error but received: %s", err)
+	}
+
+	if !hasIDs(ids, created.ID, other.ID) || len(ids) != 2 {
+		t.Errorf("expected ids %s and %s but received %v", created.ID, other.ID, ids)
+	}
+
+	if err := s.Delete(created.ID); err != nil {
+		t.Errorf("expected no error but received: %s", err)
+	}
+
+	if _, err := s.Load(created.ID); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound but received: %v", err)
+	}
+
+	if _, err := s.Save(saved); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound but received: %v", err)
+	}
+
+	if err := s.Delete(created.ID); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound but received: %v", err)
+	}
+}
+
+func checkSameGame(t *testing.T, expected *hearts.Hearts, actual *hearts.Hearts) {
+	t.Helper()
+
+	expectedJSON, _ := expected.MarshalJSON()
+	actualJSON, _ := actual.MarshalJSON()
+
+	if !bytes.Equal(expectedJSON, actualJSON) {
+		t.Errorf("expected game %s but received %s", expectedJSON, actualJSON)
+	}
+}
+
+func hasIDs(ids []string, expected ...string) bool {
+	set := map[string]bool{}
+
+	for _, id := range ids {
+		set[id] = true
+	}
+
+	for _, id := range expected {
+		if !set[id] {
+			return false
+		}
+	}
+
+	return true
+}